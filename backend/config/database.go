@@ -19,19 +19,22 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
-func ConnectDB() {
+// DSN returns the PostgreSQL connection string built from the environment
+func DSN() string {
 	host := getEnvOrDefault("DB_HOST", "localhost")
 	port := getEnvOrDefault("DB_PORT", "5433")
 	user := getEnvOrDefault("DB_USER", "tracker_user")
 	password := getEnvOrDefault("DB_PASSWORD", "tracker_pass")
 	db_name := getEnvOrDefault("DB_NAME", "internship_tracker")
 
-	dsn := fmt.Sprintf(
+	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		host, port, user, password, db_name,
 	)
+}
 
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func ConnectDB() {
+	database, err := gorm.Open(postgres.Open(DSN()), &gorm.Config{})
 	if err != nil {
 		log.Fatal("Failed to connect to database: ", err)
 	}
@@ -40,5 +43,32 @@ func ConnectDB() {
 	DB = database
 
 	// Add EmailVerification to the auto-migration
-	DB.AutoMigrate(&models.Application{}, &models.User{}, &models.EmailVerification{})
+	DB.AutoMigrate(
+		&models.Application{},
+		&models.User{},
+		&models.EmailVerification{},
+		&models.OutboxEmail{},
+		&models.ApplicationStatusChange{},
+		&models.Interview{},
+		&models.Reminder{},
+		&models.NotificationSettings{},
+		&models.Notification{},
+		&models.ApplicationEvent{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDelivery{},
+		&models.PersonalAccessToken{},
+		&models.UserIdentity{},
+		&models.OIDCLoginState{},
+		&models.DataExport{},
+		&models.Tag{},
+		&models.Company{},
+		&models.Term{},
+		&models.Offer{},
+		&models.WishlistItem{},
+		&models.InboundEmail{},
+		&models.EmailRule{},
+		&models.EmailIngestionSettings{},
+		&models.AutomationRule{},
+		&models.AutomationExecution{},
+	)
 }
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func GetJWTSecret() string {
@@ -15,6 +18,30 @@ func GetJWTSecret() string {
     return secret
 }
 
+// GetJWTIssuer returns the iss claim of the session tokens we issue
+func GetJWTIssuer() string {
+	return getEnvOrDefault("JWT_ISSUER", "internship-hub")
+}
+
+// GetJWTAudience returns the aud claim of the session tokens we issue
+func GetJWTAudience() string {
+	return getEnvOrDefault("JWT_AUDIENCE", "internship-hub-api")
+}
+
+// GetLinkSigningSecret returns the key used to sign links sent by email,
+// such as one-click unsubscribe links
+func GetLinkSigningSecret() string {
+	if secret := os.Getenv("LINK_SIGNING_SECRET"); secret != "" {
+		return secret
+	}
+	return GetJWTSecret()
+}
+
+// GetBackendURL returns the public base URL of this API, used in emailed links
+func GetBackendURL() string {
+	return strings.TrimRight(getEnvOrDefault("BACKEND_URL", "http://localhost:8080"), "/")
+}
+
 // ValidateEnv checks that all required environment variables are set
 func ValidateEnv() {
 	requiredEnvVars := []string{
@@ -48,5 +75,37 @@ func PrintConfig() {
 		}
 		return "***USING_DEFAULT***"
 	}())
+	fmt.Printf("JWT_KEYS_DIR: %s\n", getEnvOrDefault("JWT_KEYS_DIR", "(HS256 with JWT_SECRET)"))
+	fmt.Printf("JWT_ACTIVE_KEY_ID: %s\n", os.Getenv("JWT_ACTIVE_KEY_ID"))
 	fmt.Println("=====================================")
 }
+
+// GetEnvInt returns the integer value of an environment variable, or
+// defaultValue if it is unset or not a valid integer
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvBool returns the boolean value of an environment variable ("true",
+// "1", ...), or defaultValue if it is unset or invalid
+func GetEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvDuration returns the duration value of an environment variable
+// (e.g. "30s", "5m"), or defaultValue if it is unset or invalid
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
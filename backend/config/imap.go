@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// IMAPConfig is the shared mailbox inbound emails are polled from
+type IMAPConfig struct {
+	Host     string
+	Port     int
+	TLS      bool
+	Username string
+	Password string
+	Mailbox  string
+	// Address is the mailbox's email address. Users forward to it with their
+	// ingestion token as plus address, e.g. jobs+<token>@example.com.
+	Address string
+}
+
+// GetIMAPConfig reads the ingestion mailbox from IMAP_HOST, IMAP_USERNAME,
+// IMAP_PASSWORD and optionally IMAP_PORT, IMAP_TLS (default true, turn it
+// off for a local test server), IMAP_MAILBOX (default INBOX) and
+// IMAP_ADDRESS. ok is false when polling isn't configured.
+func GetIMAPConfig() (cfg IMAPConfig, ok bool) {
+	cfg = IMAPConfig{
+		Host:     os.Getenv("IMAP_HOST"),
+		TLS:      GetEnvBool("IMAP_TLS", true),
+		Username: os.Getenv("IMAP_USERNAME"),
+		Password: os.Getenv("IMAP_PASSWORD"),
+		Mailbox:  getEnvOrDefault("IMAP_MAILBOX", "INBOX"),
+		Address:  strings.ToLower(strings.TrimSpace(os.Getenv("IMAP_ADDRESS"))),
+	}
+	defaultPort := 993
+	if !cfg.TLS {
+		defaultPort = 143
+	}
+	cfg.Port = GetEnvInt("IMAP_PORT", defaultPort)
+
+	return cfg, cfg.Host != "" && cfg.Username != ""
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// OfferRates is the rate table offers are compared with
+type OfferRates struct {
+	BaseCurrency string
+	Rates        map[string]float64 // Value of one unit of each currency in BaseCurrency
+	HoursPerWeek float64
+}
+
+// GetOfferRates reads the offer rate table. OFFER_BASE_CURRENCY (default
+// USD) is the currency rates are given in and OFFER_EXCHANGE_RATES lists
+// the others, e.g. "CAD=0.73,EUR=1.08,GBP=1.27". OFFER_HOURS_PER_WEEK
+// (default 40, also used for values that aren't positive) converts hourly pay.
+func GetOfferRates() OfferRates {
+	rates := OfferRates{
+		BaseCurrency: strings.ToUpper(getEnvOrDefault("OFFER_BASE_CURRENCY", "USD")),
+		Rates:        map[string]float64{},
+		HoursPerWeek: float64(GetEnvInt("OFFER_HOURS_PER_WEEK", 40)),
+	}
+	if rates.HoursPerWeek <= 0 {
+		rates.HoursPerWeek = 40 // Hourly pay would divide by zero
+	}
+	rates.Rates[rates.BaseCurrency] = 1
+
+	for _, entry := range strings.Split(os.Getenv("OFFER_EXCHANGE_RATES"), ",") {
+		currency, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			continue
+		}
+		rates.Rates[strings.ToUpper(strings.TrimSpace(currency))] = rate
+	}
+
+	return rates
+}
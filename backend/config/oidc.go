@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// OIDCProvider is an OpenID Connect identity provider users can sign in with
+type OIDCProvider struct {
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// GetOIDCProviders reads the providers listed in OIDC_PROVIDERS (e.g.
+// "university,google"). Each one is configured with OIDC_<NAME>_ISSUER,
+// OIDC_<NAME>_CLIENT_ID and OIDC_<NAME>_CLIENT_SECRET, and optionally
+// OIDC_<NAME>_DISPLAY_NAME, OIDC_<NAME>_REDIRECT_URL and OIDC_<NAME>_SCOPES.
+func GetOIDCProviders() map[string]OIDCProvider {
+	providers := make(map[string]OIDCProvider)
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		provider := OIDCProvider{
+			Name:         name,
+			DisplayName:  getEnvOrDefault(prefix+"DISPLAY_NAME", name),
+			Issuer:       strings.TrimRight(os.Getenv(prefix+"ISSUER"), "/"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  getEnvOrDefault(prefix+"REDIRECT_URL", GetBackendURL()+"/auth/oidc/"+name+"/callback"),
+			Scopes:       strings.Fields(getEnvOrDefault(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			continue
+		}
+		providers[name] = provider
+	}
+
+	return providers
+}
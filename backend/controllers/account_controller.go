@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ChangePassword sets a new password and signs out every other session. The
+// response contains a fresh token for the current session. Accounts without
+// a password, made through SSO, set their first one without current_password.
+func ChangePassword(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password" binding:"required,min=8"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid password data: " + err.Error()})
+		return
+	}
+
+	if !checkPassword(user, input.CurrentPassword) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+		return
+	}
+
+	user.Password = string(passwordHash)
+	user.SessionVersion++
+	if err := config.DB.Model(user).Updates(map[string]interface{}{
+		"password":        user.Password,
+		"session_version": user.SessionVersion,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password: " + err.Error()})
+		return
+	}
+
+	token, err := generateAuthToken(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password changed successfully. Other sessions have been signed out.",
+		"token":   token,
+	})
+}
+
+// RequestEmailChange sends a confirmation link to the new address. The email
+// only changes once the link is opened, see confirmEmailChange. Accounts
+// without a password don't send one.
+func RequestEmailChange(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input struct {
+		Email    string `json:"email" binding:"required,email"`
+		Password string `json:"password"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email data: " + err.Error()})
+		return
+	}
+
+	if !checkPassword(user, input.Password) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Password is incorrect"})
+		return
+	}
+	if strings.EqualFold(input.Email, user.Email) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "That is already your email address"})
+		return
+	}
+	if emailTaken(config.DB, input.Email, user.ID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email already used"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Only the latest request can be confirmed
+		if err := tx.Where("user_id = ? AND purpose = ?", user.ID, models.VerificationEmailChange).
+			Delete(&models.EmailVerification{}).Error; err != nil {
+			return err
+		}
+
+		token := services.GenerateVerificationToken()
+		verification := models.EmailVerification{
+			UserID:    user.ID,
+			Token:     token,
+			Purpose:   models.VerificationEmailChange,
+			NewEmail:  input.Email,
+			ExpiresAt: time.Now().Add(24 * time.Hour),
+		}
+		if err := tx.Create(&verification).Error; err != nil {
+			return err
+		}
+
+		return services.EnqueueEmailChangeEmails(tx, user.Email, input.Email, token)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request email change"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Check " + input.Email + " for a link to confirm your new email address.",
+	})
+}
+
+// confirmEmailChange switches the user to the verified new address, called
+// by VerifyEmail for email change tokens
+func confirmEmailChange(c *gin.Context, user *models.User, verification *models.EmailVerification) {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if emailTaken(tx, verification.NewEmail, user.ID) {
+			return errEmailTaken
+		}
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"email":       verification.NewEmail,
+			"is_verified": true,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(verification).Error
+	})
+	if err == errEmailTaken {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email already used"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Your email address has been changed to " + verification.NewEmail + ".",
+	})
+}
+
+var errEmailTaken = errors.New("email already used")
+
+func emailTaken(db *gorm.DB, email string, exceptUserID uint) bool {
+	var count int64
+	db.Model(&models.User{}).Where("LOWER(email) = LOWER(?) AND id <> ?", email, exceptUserID).Count(&count)
+	return count > 0
+}
+
+var validUsername = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,32}$`)
+
+// ChangeUsername renames the user, usernames are unique regardless of case
+func ChangeUsername(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input struct {
+		Username string `json:"username" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid username data: " + err.Error()})
+		return
+	}
+
+	username := strings.TrimSpace(input.Username)
+	if !validUsername.MatchString(username) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Username must be 3-32 letters, numbers, dots, dashes or underscores"})
+		return
+	}
+
+	var count int64
+	config.DB.Model(&models.User{}).Where("LOWER(username) = LOWER(?) AND id <> ?", username, user.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username already used"})
+		return
+	}
+
+	if err := config.DB.Model(user).Update("username", username).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change username: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// DeleteAccount schedules the account for deletion. Until the grace period
+// ends the user can still log in and restore it, afterwards the account
+// deletion worker removes it with all of its data and resume files.
+func DeleteAccount(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input struct {
+		Confirm string `json:"confirm" binding:"required"` // Must be the username
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || input.Confirm != user.Username {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Type your username to confirm deleting your account"})
+		return
+	}
+
+	if user.DeletionScheduledAt != nil {
+		c.JSON(http.StatusOK, gin.H{"deletion_scheduled_at": user.DeletionScheduledAt})
+		return
+	}
+
+	deleteAt := time.Now().Add(services.AccountDeletionGracePeriod())
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("deletion_scheduled_at", deleteAt).Error; err != nil {
+			return err
+		}
+		return services.EnqueueAccountDeletionEmail(tx, user.Email, deleteAt)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":               "Your account will be deleted. Log in before then to restore it.",
+		"deletion_scheduled_at": deleteAt,
+	})
+}
+
+// RestoreAccount cancels a scheduled account deletion
+func RestoreAccount(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if user.DeletionScheduledAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Account is not scheduled for deletion"})
+		return
+	}
+
+	if err := config.DB.Model(user).Update("deletion_scheduled_at", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore account: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account restored successfully"})
+}
+
+// checkPassword reports whether password is the user's. Accounts made
+// through SSO have no password, they only sign in through the provider, so
+// anything passes until they set one.
+func checkPassword(user *models.User, password string) bool {
+	if user.Password == "" {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil
+}
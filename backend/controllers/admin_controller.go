@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetOutboxEmails lists outbox emails that have not been delivered yet.
+// Use ?status=dead to only see emails that exhausted their retries.
+func GetOutboxEmails(c *gin.Context) {
+	query := config.DB.Order("created_at DESC").Limit(200)
+
+	switch status := c.Query("status"); status {
+	case "":
+		query = query.Where("status <> ?", models.OutboxSent)
+	case string(models.OutboxPending), string(models.OutboxSent), string(models.OutboxDead):
+		query = query.Where("status = ?", status)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status filter"})
+		return
+	}
+
+	var emails []models.OutboxEmail
+	if err := query.Find(&emails).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load outbox: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, emails)
+}
+
+// RetryOutboxEmail puts a failed email back in the queue with a fresh retry budget
+func RetryOutboxEmail(c *gin.Context) {
+	var email models.OutboxEmail
+	if err := config.DB.First(&email, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Outbox email not found"})
+		return
+	}
+
+	if email.Status == models.OutboxSent {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email was already sent"})
+		return
+	}
+
+	email.Status = models.OutboxPending
+	email.Attempts = 0
+	email.NextAttemptAt = time.Now()
+
+	if err := config.DB.Save(&email).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry email: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, email)
+}
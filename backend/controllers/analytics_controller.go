@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetSeasonAnalytics compares recruiting seasons: stats per term, and the
+// same season across years. Use ?archived=true to only include archived
+// applications, i.e. finished seasons.
+func GetSeasonAnalytics(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := config.DB.Where("user_id = ?", user.ID)
+	if c.Query("archived") == "true" {
+		query = query.Where("archived = ?", true)
+	}
+
+	var apps []models.Application
+	if err := query.Find(&apps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load applications: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, services.BuildSeasonAnalytics(apps))
+}
+
+// GetLocationAnalytics compares outcomes by work mode, country and city.
+// ?archived=true works as for seasons.
+func GetLocationAnalytics(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := config.DB.Where("user_id = ?", user.ID)
+	if c.Query("archived") == "true" {
+		query = query.Where("archived = ?", true)
+	}
+
+	var apps []models.Application
+	if err := query.Find(&apps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load applications: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, services.BuildLocationAnalytics(apps))
+}
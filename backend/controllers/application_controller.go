@@ -3,14 +3,18 @@ package controllers
 import (
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
 	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"fmt"
 	"os"
 	"time"
+	"unicode/utf8"
 )
 
 func getCurrentUser(c *gin.Context) (*models.User, error) {
@@ -27,6 +31,36 @@ func getCurrentUser(c *gin.Context) (*models.User, error) {
 	return &user, nil
 }
 
+// applicationListQuery applies the filters of the application list to query
+func applicationListQuery(c *gin.Context, query *gorm.DB, user *models.User) *gorm.DB {
+	query = query.Where("user_id = ?", user.ID)
+	query = filterByTags(c, query)
+	query = filterByTerm(c, query, user)
+
+	// Archived applications are hidden unless asked for
+	switch c.Query("archived") {
+	case "true":
+		query = query.Where("archived = ?", true)
+	case "all":
+	default:
+		query = query.Where("archived = ?", false)
+	}
+
+	if workMode := c.Query("work_mode"); workMode != "" {
+		query = query.Where("work_mode = ?", workMode)
+	}
+	if country := c.Query("country"); country != "" {
+		query = query.Where("country = ?", strings.ToUpper(country))
+	}
+	if region := c.Query("region"); region != "" {
+		query = query.Where("LOWER(region) = LOWER(?)", region)
+	}
+	if city := c.Query("city"); city != "" {
+		query = query.Where("LOWER(city) = LOWER(?)", city)
+	}
+	return query
+}
+
 func GetApplications(c *gin.Context) {
 	user, err := getCurrentUser(c)
 	if err != nil {
@@ -34,11 +68,32 @@ func GetApplications(c *gin.Context) {
 		return
 	}
 
+	query := applicationListQuery(c, config.DB.Preload("Tags"), user)
+
 	var applications []models.Application
-	config.DB.Where("user_id = ?", user.ID).Order("applied_date DESC, company ASC").Find(&applications)
+	query.Order("applied_date DESC, company ASC").Find(&applications)
 	c.JSON(http.StatusOK, applications)
 }
 
+// GetApplicationFacets counts the listed applications per work mode,
+// country, region and city. It takes the same filters as GetApplications.
+func GetApplicationFacets(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := applicationListQuery(c, config.DB.Model(&models.Application{}), user)
+	facets, err := services.BuildLocationFacets(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count applications: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, facets)
+}
+
 func GetApplicationByID(c *gin.Context) {
 	user, err := getCurrentUser(c)
 	if err != nil {
@@ -49,7 +104,7 @@ func GetApplicationByID(c *gin.Context) {
 	id := c.Param("id")
 	var application models.Application
 
-	if err := config.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&application).Error; err != nil {
+	if err := config.DB.Preload("Tags").Where("id = ? AND user_id = ?", id, user.ID).First(&application).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
@@ -122,6 +177,11 @@ func CreateApplication(c *gin.Context) {
 	}
 	status = models.ApplicationStatus(statusVal)
 
+	company, ok := companyFromForm(c, user.ID)
+	if !ok {
+		return
+	}
+
 	app := models.Application{
 		Company:     c.PostForm("company"),
 		Position:    c.PostForm("position"),
@@ -133,8 +193,23 @@ func CreateApplication(c *gin.Context) {
 		ResumeURL:   "/uploads/" + filename,
 		UserID:      user.ID, // Use authenticated user's ID
 	}
+	if !applyLocationForm(c, &app) || !applyPostingForm(c, &app) {
+		return
+	}
 
-	if err := config.DB.Create(&app).Error; err != nil {
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := linkApplicationCompany(tx, &app, company); err != nil {
+			return err
+		}
+		if err := linkApplicationTerm(tx, &app, user); err != nil {
+			return err
+		}
+		if err := tx.Create(&app).Error; err != nil {
+			return err
+		}
+		return services.PublishApplicationEvent(tx, models.EventApplicationCreated, &app)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create application: " + err.Error()})
 		return
 	}
@@ -142,6 +217,8 @@ func CreateApplication(c *gin.Context) {
 	c.JSON(http.StatusCreated, app)
 }
 
+// DeleteApplication moves an application to the trash. It can be restored
+// until the trash purge worker deletes it for good after TRASH_RETENTION.
 func DeleteApplication(c *gin.Context) {
 	user, err := getCurrentUser(c)
 	if err != nil {
@@ -157,32 +234,92 @@ func DeleteApplication(c *gin.Context) {
 		return
 	}
 
-	// Try to delete the file first before deleting from database
-	if app.ResumeURL != "" {
-		filePath := "." + app.ResumeURL
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&app).Error; err != nil {
+			return err
+		}
+		return services.PublishApplicationEvent(tx, models.EventApplicationDeleted, &app)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete application: " + err.Error()})
+		return
+	}
 
-		fmt.Printf("DEBUG: Attempting to delete file at path: %s\n", filePath)
-		fmt.Printf("DEBUG: Resume URL from DB: %s\n", app.ResumeURL)
+	c.JSON(http.StatusOK, gin.H{"message": "Application moved to trash"})
+}
 
-		if _, err := os.Stat(filePath); os.IsNotExist(err) {
-			fmt.Printf("DEBUG: File does not exist at path: %s\n", filePath)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Resume file not found on server: " + filePath})
-			return
-		}
+// GetTrash lists the user's deleted applications, most recently deleted first
+func GetTrash(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
 
-		if err := os.Remove(filePath); err != nil {
-			fmt.Printf("DEBUG: Failed to delete file %s: %v\n", filePath, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete resume file: " + err.Error()})
-			return
+	var applications []models.Application
+	config.DB.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", user.ID).
+		Order("deleted_at DESC").Find(&applications)
+
+	c.JSON(http.StatusOK, gin.H{
+		"applications":   applications,
+		"retention_days": int(services.TrashRetention().Hours() / 24),
+	})
+}
+
+// RestoreApplication takes an application back out of the trash
+func RestoreApplication(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var app models.Application
+	if err := config.DB.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", c.Param("id"), user.ID).
+		First(&app).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found in trash"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&app).Update("deleted_at", nil).Error; err != nil {
+			return err
 		}
+		return services.PublishApplicationEvent(tx, models.EventApplicationCreated, &app)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore application: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, app)
+}
 
-		fmt.Printf("DEBUG: Successfully deleted file: %s\n", filePath)
+// PurgeApplication permanently deletes an application from the trash
+func PurgeApplication(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
 	}
 
-	if err := config.DB.Delete(&app).Error; err != nil {
+	var app models.Application
+	if err := config.DB.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", c.Param("id"), user.ID).
+		First(&app).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found in trash"})
+		return
+	}
+
+	var files []string
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		files, err = services.PurgeApplication(tx, &app)
+		return err
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete application: " + err.Error()})
 		return
 	}
+	services.RemoveFiles(files)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Application deleted successfully"})
 }
@@ -210,7 +347,48 @@ func UpdateApplication(c *gin.Context) {
 		return
 	}
 
-	// Handle optional file upload (new resume)
+	// Parse applied date
+	appliedDate, err := time.Parse(time.RFC3339, c.PostForm("applied_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format"})
+		return
+	}
+
+	// Parse status
+	statusInt := c.PostForm("status")
+	var status models.ApplicationStatus
+	if statusInt == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status is required"})
+		return
+	}
+	var statusVal uint8
+	_, err = fmt.Sscanf(statusInt, "%d", &statusVal)
+	if err != nil || statusVal > uint8(models.StatusRejected) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status value"})
+		return
+	}
+	status = models.ApplicationStatus(statusVal)
+
+	company, ok := companyFromForm(c, user.ID)
+	if !ok {
+		return
+	}
+
+	// Update the application
+	previousStatus := app.Status
+	previousCompany := app.Company
+	app.Company = c.PostForm("company")
+	app.Position = c.PostForm("position")
+	app.Status = status
+	app.Location = c.PostForm("location")
+	app.AppliedDate = appliedDate
+	app.Term = c.PostForm("term")
+	app.Note = c.PostForm("note")
+	if !applyLocationForm(c, &app) || !applyPostingForm(c, &app) {
+		return
+	}
+
+	// Handle optional file upload (new resume), once the rest of the form is valid
 	file, fileHeader, err := c.Request.FormFile("resume")
 	var newResumeURL string
 	if err == nil {
@@ -238,57 +416,51 @@ func UpdateApplication(c *gin.Context) {
 			return
 		}
 
-		// Delete old resume file if it exists
-		if app.ResumeURL != "" {
-			oldFilePath := "." + app.ResumeURL
-			if err := os.Remove(oldFilePath); err != nil {
-				fmt.Printf("Warning: Failed to delete old resume file %s: %v\n", oldFilePath, err)
-			}
-		}
-
 		newResumeURL = "/uploads/" + filename
 	} else {
 		// No new file uploaded, keep existing resume URL
 		newResumeURL = app.ResumeURL
 	}
 
-	// Parse applied date
-	appliedDate, err := time.Parse(time.RFC3339, c.PostForm("applied_date"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format"})
-		return
-	}
-
-	// Parse status
-	statusInt := c.PostForm("status")
-	var status models.ApplicationStatus
-	if statusInt == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Status is required"})
-		return
-	}
-	var statusVal uint8
-	_, err = fmt.Sscanf(statusInt, "%d", &statusVal)
-	if err != nil || statusVal > uint8(models.StatusRejected) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status value"})
-		return
-	}
-	status = models.ApplicationStatus(statusVal)
-
-	// Update the application
-	app.Company = c.PostForm("company")
-	app.Position = c.PostForm("position")
-	app.Status = status
-	app.Location = c.PostForm("location")
-	app.AppliedDate = appliedDate
-	app.Term = c.PostForm("term")
-	app.Note = c.PostForm("note")
+	oldResumeURL := app.ResumeURL
 	app.ResumeURL = newResumeURL
 
-	if err := config.DB.Save(&app).Error; err != nil {
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Spelling the same company differently keeps its canonical name
+		if company == nil && app.CompanyID != nil &&
+			services.NormalizeCompanyName(app.Company) == services.NormalizeCompanyName(previousCompany) {
+			app.Company = previousCompany
+		} else if err := linkApplicationCompany(tx, &app, company); err != nil {
+			return err
+		}
+		if err := linkApplicationTerm(tx, &app, user); err != nil {
+			return err
+		}
+		if err := tx.Save(&app).Error; err != nil {
+			return err
+		}
+		if err := services.RecordStatusChange(tx, &app, previousStatus); err != nil {
+			return err
+		}
+		return services.PublishApplicationEvent(tx, models.EventApplicationUpdated, &app)
+	})
+	if err != nil {
+		// Keep the old resume, the new one was never saved
+		if app.ResumeURL != oldResumeURL {
+			os.Remove("." + app.ResumeURL)
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application: " + err.Error()})
 		return
 	}
 
+	// Delete old resume file if it was replaced
+	if oldResumeURL != "" && oldResumeURL != app.ResumeURL {
+		oldFilePath := "." + oldResumeURL
+		if err := os.Remove(oldFilePath); err != nil {
+			fmt.Printf("Warning: Failed to delete old resume file %s: %v\n", oldFilePath, err)
+		}
+	}
+
 	c.JSON(http.StatusOK, app)
 }
 
@@ -325,12 +497,92 @@ func UpdateApplicationStatus(c *gin.Context) {
 	}
 
 	// Update only the status field
+	previousStatus := app.Status
 	app.Status = statusUpdate.Status
 
-	if err := config.DB.Save(&app).Error; err != nil {
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&app).Error; err != nil {
+			return err
+		}
+		if err := services.RecordStatusChange(tx, &app, previousStatus); err != nil {
+			return err
+		}
+		return services.PublishApplicationEvent(tx, models.EventApplicationUpdated, &app)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application status: " + err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, app)
 }
+
+// applyLocationForm sets the structured location parsed from the location
+// form field, overridden by the city, region, country and work_mode fields
+// when given. It responds with an error and returns false if one is invalid.
+func applyLocationForm(c *gin.Context, app *models.Application) bool {
+	services.ApplyLocation(app)
+
+	if workMode := c.PostForm("work_mode"); workMode != "" {
+		switch models.WorkMode(workMode) {
+		case models.WorkModeRemote, models.WorkModeHybrid, models.WorkModeOnsite:
+			app.WorkMode = models.WorkMode(workMode)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Work mode must be remote, hybrid or onsite"})
+			return false
+		}
+	}
+	if country := strings.TrimSpace(c.PostForm("country")); country != "" {
+		if len(country) != 2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Country must be a two-letter code like US"})
+			return false
+		}
+		app.Country = strings.ToUpper(country)
+	}
+	if region := strings.TrimSpace(c.PostForm("region")); region != "" {
+		if utf8.RuneCountInString(region) > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Region must be at most 100 characters"})
+			return false
+		}
+		app.Region = region
+	}
+	if city := strings.TrimSpace(c.PostForm("city")); city != "" {
+		if utf8.RuneCountInString(city) > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "City must be at most 100 characters"})
+			return false
+		}
+		app.City = city
+	}
+	return true
+}
+
+// applyPostingForm sets the posting_url, description and deadline form
+// fields that were sent, so clients that don't know them keep the values.
+// An empty deadline clears it. It responds with an error and returns false
+// if one is invalid.
+func applyPostingForm(c *gin.Context, app *models.Application) bool {
+	if postingURL, ok := c.GetPostForm("posting_url"); ok {
+		postingURL = strings.TrimSpace(postingURL)
+		if postingURL != "" && !services.IsHTTPURL(postingURL) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Posting URL must be an http or https URL"})
+			return false
+		}
+		app.PostingURL = postingURL
+	}
+	if description, ok := c.GetPostForm("description"); ok {
+		app.Description = strings.TrimSpace(description)
+	}
+	if deadline, ok := c.GetPostForm("deadline"); ok {
+		if deadline == "" {
+			app.Deadline = nil
+		} else {
+			parsed, err := time.Parse(time.RFC3339, deadline)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deadline format"})
+				return false
+			}
+			app.Deadline = &parsed
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type archiveTermInput struct {
+	Term     string                     `json:"term" binding:"required"`
+	Statuses []models.ApplicationStatus `json:"statuses"` // Optional, e.g. only accepted and rejected
+}
+
+// ArchiveTerm archives every application of a term, optionally only those
+// with one of the given statuses
+func ArchiveTerm(c *gin.Context) {
+	setTermArchived(c, true)
+}
+
+// UnarchiveTerm brings a term's archived applications back to the active list
+func UnarchiveTerm(c *gin.Context) {
+	setTermArchived(c, false)
+}
+
+func setTermArchived(c *gin.Context, archived bool) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input archiveTermInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid archive data: " + err.Error()})
+		return
+	}
+
+	query := config.DB.Where("user_id = ? AND archived = ?", user.ID, !archived)
+	if season, year, ok := services.ParseTerm(input.Term); ok {
+		query = query.Where("term_id IN (SELECT id FROM terms WHERE season = ? AND year = ?)", season, year)
+	} else {
+		query = query.Where("LOWER(term) = LOWER(?)", strings.TrimSpace(input.Term))
+	}
+	if len(input.Statuses) > 0 {
+		query = query.Where("status IN ?", input.Statuses)
+	}
+
+	var apps []models.Application
+	if err := query.Find(&apps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load applications: " + err.Error()})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range apps {
+			if err := services.SetArchived(tx, &apps[i], archived); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update applications: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"term": input.Term, "archived": archived, "count": len(apps)})
+}
@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"net/http"
-	"os"
 	"time"
 	"strings"
 	
@@ -10,8 +9,8 @@ import (
 	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
 	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 func CreateUser(c *gin.Context) {
@@ -48,28 +47,30 @@ func CreateUser(c *gin.Context) {
         IsVerified: false,
     }
 
-	if err := config.DB.Create(&user).Error; err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
-        return
-    }
-
-	// Generate verification token
-    token := services.GenerateVerificationToken()
-    verification := models.EmailVerification{
-        UserID:    user.ID,
-        Token:     token,
-        ExpiresAt: time.Now().Add(24 * time.Hour),
-    }
-
-	if err := config.DB.Create(&verification).Error; err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create verification token"})
-        return
-    }
-
-	if err := services.SendVerificationEmail(user.Email, token); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
-        return
-    }
+	// Create the user, its verification token and the verification email in
+	// one transaction so a failure never leaves a half-registered account
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		token := services.GenerateVerificationToken()
+		verification := models.EmailVerification{
+			UserID:    user.ID,
+			Token:     token,
+			Purpose:   models.VerificationSignup,
+			ExpiresAt: time.Now().Add(24 * time.Hour),
+		}
+		if err := tx.Create(&verification).Error; err != nil {
+			return err
+		}
+
+		return services.EnqueueVerificationEmail(tx, user.Email, token)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
         "message": "User created successfully. Please check your email for verification.",
@@ -77,6 +78,11 @@ func CreateUser(c *gin.Context) {
     })
 }
 
+// generateAuthToken issues the 24-hour session JWT returned on login
+func generateAuthToken(user *models.User) (string, error) {
+	return services.IssueAuthToken(user)
+}
+
 func Login(c *gin.Context) {
 	var loginInput models.LoginInput
 
@@ -112,12 +118,7 @@ func Login(c *gin.Context) {
         return
     }
 
-	generateToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-        "id":  userFound.ID,
-        "exp": time.Now().Add(time.Hour * 24).Unix(),
-    })
-
-	token, err := generateToken.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	token, err := generateAuthToken(&userFound)
     if err != nil {
         c.JSON(http.StatusBadRequest, gin.H{"error": "failed to generate token"})
         return
@@ -152,6 +153,11 @@ func VerifyEmail(c *gin.Context) {
         return
     }
 
+	if verification.Purpose == models.VerificationEmailChange {
+		confirmEmailChange(c, &user, &verification)
+		return
+	}
+
 	user.IsVerified = true
     if err := config.DB.Save(&user).Error; err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify user"})
@@ -186,24 +192,30 @@ func ResendVerification(c *gin.Context) {
         return
     }
 
-    // Delete old verification tokens
-    config.DB.Where("user_id = ?", user.ID).Delete(&models.EmailVerification{})
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Delete old verification tokens
+		if err := tx.Where("user_id = ? AND purpose = ?", user.ID, models.VerificationSignup).Delete(&models.EmailVerification{}).Error; err != nil {
+			return err
+		}
 
-    // Generate new token
-    token := services.GenerateVerificationToken()
-    verification := models.EmailVerification{
-        UserID:    user.ID,
-        Token:     token,
-        ExpiresAt: time.Now().Add(24 * time.Hour),
-    }
-    
-    config.DB.Create(&verification)
+		// Generate new token
+		token := services.GenerateVerificationToken()
+		verification := models.EmailVerification{
+			UserID:    user.ID,
+			Token:     token,
+			Purpose:   models.VerificationSignup,
+			ExpiresAt: time.Now().Add(24 * time.Hour),
+		}
+		if err := tx.Create(&verification).Error; err != nil {
+			return err
+		}
 
-    // Send new verification email
-    if err := services.SendVerificationEmail(user.Email, token); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
-        return
-    }
+		return services.EnqueueVerificationEmail(tx, user.Email, token)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+		return
+	}
 
     c.JSON(http.StatusOK, gin.H{
         "message": "Verification email sent successfully",
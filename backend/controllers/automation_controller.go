@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type automationRuleInput struct {
+	Name          string                       `json:"name" binding:"required,max=100"`
+	Enabled       *bool                        `json:"enabled"` // Defaults to true
+	Trigger       string                       `json:"trigger" binding:"required"`
+	TriggerStatus *models.ApplicationStatus    `json:"trigger_status"`
+	DelayDays     int                          `json:"delay_days"`
+	Conditions    []models.AutomationCondition `json:"conditions"`
+	Actions       []models.AutomationAction    `json:"actions"`
+}
+
+// findAutomationRule loads one of the current user's automation rules, responding 404 if it doesn't exist
+func findAutomationRule(c *gin.Context, userID uint) (*models.AutomationRule, bool) {
+	var rule models.AutomationRule
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return nil, false
+	}
+	return &rule, true
+}
+
+func GetAutomationRules(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var rules []models.AutomationRule
+	config.DB.Where("user_id = ?", user.ID).Order("id ASC").Find(&rules)
+	c.JSON(http.StatusOK, rules)
+}
+
+func CreateAutomationRule(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input automationRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule data: " + err.Error()})
+		return
+	}
+
+	rule := models.AutomationRule{UserID: user.ID, CheckedUntil: time.Now()}
+	if !applyAutomationRuleInput(c, &rule, &input) {
+		return
+	}
+
+	if err := config.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create rule: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateAutomationRule replaces a rule. Events from before it was enabled
+// again, or before its trigger changed, don't trigger it.
+func UpdateAutomationRule(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	rule, ok := findAutomationRule(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var input automationRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule data: " + err.Error()})
+		return
+	}
+
+	wasEnabled, trigger := rule.Enabled, rule.Trigger
+	if !applyAutomationRuleInput(c, rule, &input) {
+		return
+	}
+	if (rule.Enabled && !wasEnabled) || rule.Trigger != trigger {
+		rule.CheckedUntil = time.Now()
+	}
+
+	if err := config.DB.Save(rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update rule: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteAutomationRule deletes a rule along with its execution log
+func DeleteAutomationRule(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	rule, ok := findAutomationRule(c, user.ID)
+	if !ok {
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", rule.ID).Delete(&models.AutomationExecution{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(rule).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete rule: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rule deleted successfully"})
+}
+
+// PreviewAutomationRule shows which applications a rule sent in the body
+// would act on now, and what it would do, without changing anything.
+// Events of the last ?days=30 are considered as if the rule had existed.
+func PreviewAutomationRule(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input automationRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule data: " + err.Error()})
+		return
+	}
+	rule := models.AutomationRule{UserID: user.ID}
+	if !applyAutomationRuleInput(c, &rule, &input) {
+		return
+	}
+
+	previewAutomationRule(c, &rule, 30)
+}
+
+// PreviewSavedAutomationRule shows what a rule's next run would do. With
+// ?days= it considers the events of the last days instead of the ones
+// since its last run, leaving out those it already acted on.
+func PreviewSavedAutomationRule(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	rule, ok := findAutomationRule(c, user.ID)
+	if !ok {
+		return
+	}
+
+	previewAutomationRule(c, rule, 0)
+}
+
+// previewAutomationRule responds with the rule's matches. Events since the
+// rule's CheckedUntil are considered, or those of the last ?days= (or
+// defaultDays when CheckedUntil isn't set).
+func previewAutomationRule(c *gin.Context, rule *models.AutomationRule, defaultDays int) {
+	now := time.Now()
+	since := rule.CheckedUntil
+	days := defaultDays
+	if value := c.Query("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+			return
+		}
+		days = n
+	}
+	if days > 0 {
+		since = now.AddDate(0, 0, -days-rule.DelayDays)
+	}
+
+	matches, err := services.EvaluateAutomationRule(config.DB, rule, since, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to evaluate rule: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dry_run": true, "count": len(matches), "matches": matches})
+}
+
+// GetAutomationExecutions lists what rules did, newest first.
+// ?rule_id= and ?application_id= narrow it down, ?result=failed only lists failures.
+func GetAutomationExecutions(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := config.DB.Where("user_id = ?", user.ID)
+	if ruleID := c.Query("rule_id"); ruleID != "" {
+		query = query.Where("rule_id = ?", ruleID)
+	}
+	if applicationID := c.Query("application_id"); applicationID != "" {
+		query = query.Where("application_id = ?", applicationID)
+	}
+	if result := c.Query("result"); result != "" {
+		query = query.Where("result = ?", result)
+	}
+
+	var executions []models.AutomationExecution
+	query.Order("created_at DESC, id DESC").Limit(200).Find(&executions)
+	c.JSON(http.StatusOK, executions)
+}
+
+// applyAutomationRuleInput copies the input onto the rule and validates it,
+// responding 400 and returning false if it isn't valid
+func applyAutomationRuleInput(c *gin.Context, rule *models.AutomationRule, input *automationRuleInput) bool {
+	rule.Name = strings.TrimSpace(input.Name)
+	rule.Enabled = input.Enabled == nil || *input.Enabled
+	rule.Trigger = input.Trigger
+	rule.TriggerStatus = input.TriggerStatus
+	rule.DelayDays = input.DelayDays
+	rule.Conditions = input.Conditions
+	rule.Actions = input.Actions
+	if rule.Conditions == nil {
+		rule.Conditions = []models.AutomationCondition{}
+	}
+
+	if err := services.ValidateAutomationRule(rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule: " + err.Error()})
+		return false
+	}
+	return true
+}
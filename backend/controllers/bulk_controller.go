@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Bulk actions
+const (
+	BulkSetStatus = "set_status"
+	BulkAddTag    = "add_tag"
+	BulkRemoveTag = "remove_tag"
+	BulkArchive   = "archive"
+	BulkUnarchive = "unarchive"
+	BulkDelete    = "delete"
+	BulkSetTerm   = "set_term"
+)
+
+type bulkInput struct {
+	IDs    []uint                    `json:"ids" binding:"required,min=1,max=500"`
+	Action string                    `json:"action" binding:"required"`
+	Status *models.ApplicationStatus `json:"status"` // For set_status
+	Term   string                    `json:"term"`   // For set_term
+	TagID  uint                      `json:"tag_id"` // For add_tag and remove_tag
+}
+
+type bulkResult struct {
+	ID    uint   `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// BulkUpdateApplications applies one action to many applications in a
+// single transaction. IDs that aren't the user's are reported as not found
+// and left alone; if anything else fails, nothing is changed.
+func BulkUpdateApplications(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input bulkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bulk data: " + err.Error()})
+		return
+	}
+
+	apply, err := bulkAction(&input, user.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var apps []models.Application
+	if err := config.DB.Where("id IN ? AND user_id = ?", input.IDs, user.ID).Find(&apps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load applications: " + err.Error()})
+		return
+	}
+	owned := make(map[uint]*models.Application, len(apps))
+	for i := range apps {
+		owned[apps[i].ID] = &apps[i]
+	}
+
+	results := make([]bulkResult, 0, len(input.IDs))
+	seen := make(map[uint]bool, len(input.IDs))
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, id := range input.IDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			app, ok := owned[id]
+			if !ok {
+				results = append(results, bulkResult{ID: id, Error: "Application not found"})
+				continue
+			}
+			if err := apply(tx, app); err != nil {
+				return err
+			}
+			results = append(results, bulkResult{ID: id, OK: true})
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update applications, nothing was changed: " + err.Error()})
+		return
+	}
+
+	succeeded := 0
+	for _, result := range results {
+		if result.OK {
+			succeeded++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"action":    input.Action,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"results":   results,
+	})
+}
+
+type bulkApplyFunc func(tx *gorm.DB, app *models.Application) error
+
+// bulkAction validates the input for its action and returns the function
+// that applies it to one application
+func bulkAction(input *bulkInput, userID uint) (bulkApplyFunc, error) {
+	switch input.Action {
+	case BulkSetStatus:
+		if input.Status == nil || *input.Status > models.StatusRejected {
+			return nil, errors.New("A valid status is required")
+		}
+		status := *input.Status
+		return func(tx *gorm.DB, app *models.Application) error {
+			previous := app.Status
+			app.Status = status
+			if err := tx.Model(app).Update("status", status).Error; err != nil {
+				return err
+			}
+			if err := services.RecordStatusChange(tx, app, previous); err != nil {
+				return err
+			}
+			return services.PublishApplicationEvent(tx, models.EventApplicationUpdated, app)
+		}, nil
+
+	case BulkSetTerm:
+		term := strings.TrimSpace(input.Term)
+		if term == "" {
+			return nil, errors.New("A term is required")
+		}
+		return func(tx *gorm.DB, app *models.Application) error {
+			app.Term = term
+			if err := services.LinkTerm(tx, app); err != nil {
+				return err
+			}
+			if err := tx.Model(app).Updates(map[string]interface{}{
+				"term":    app.Term,
+				"term_id": app.TermID,
+			}).Error; err != nil {
+				return err
+			}
+			return services.PublishApplicationEvent(tx, models.EventApplicationUpdated, app)
+		}, nil
+
+	case BulkArchive, BulkUnarchive:
+		archived := input.Action == BulkArchive
+		return func(tx *gorm.DB, app *models.Application) error {
+			if app.Archived == archived {
+				return nil
+			}
+			return services.SetArchived(tx, app, archived)
+		}, nil
+
+	case BulkDelete:
+		return func(tx *gorm.DB, app *models.Application) error {
+			if err := tx.Delete(app).Error; err != nil {
+				return err
+			}
+			return services.PublishApplicationEvent(tx, models.EventApplicationDeleted, app)
+		}, nil
+
+	case BulkAddTag, BulkRemoveTag:
+		var tag models.Tag
+		if err := config.DB.Where("id = ? AND user_id = ?", input.TagID, userID).First(&tag).Error; err != nil {
+			return nil, errors.New("Tag not found")
+		}
+		return func(tx *gorm.DB, app *models.Application) error {
+			var err error
+			if input.Action == BulkAddTag {
+				err = tx.Model(app).Association("Tags").Append(&tag)
+			} else {
+				err = tx.Model(app).Association("Tags").Delete(&tag)
+			}
+			if err != nil {
+				return err
+			}
+			return publishTagChange(tx, app)
+		}, nil
+
+	default:
+		return nil, errors.New("Unknown action: " + input.Action)
+	}
+}
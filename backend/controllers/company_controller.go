@@ -0,0 +1,380 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type companyInput struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	Aliases      []string `json:"aliases"`
+	Website      string   `json:"website"`
+	Industry     string   `json:"industry" binding:"max=100"`
+	Headquarters string   `json:"headquarters" binding:"max=100"`
+}
+
+// findCompany loads one of the current user's companies, responding 404 if it doesn't exist
+func findCompany(c *gin.Context, userID uint) (*models.Company, bool) {
+	var company models.Company
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&company).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return nil, false
+	}
+	return &company, true
+}
+
+// GetCompanies lists the user's companies with how many applications each
+// has. Applications in the trash aren't counted.
+func GetCompanies(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var companies []struct {
+		models.Company
+		ApplicationCount int `json:"application_count"`
+	}
+	config.DB.Model(&models.Company{}).
+		Select("companies.*, COUNT(applications.id) AS application_count").
+		Joins("LEFT JOIN applications ON applications.company_id = companies.id AND applications.deleted_at IS NULL").
+		Where("companies.user_id = ?", user.ID).
+		Group("companies.id").
+		Order("companies.name ASC").
+		Scan(&companies)
+
+	c.JSON(http.StatusOK, companies)
+}
+
+// GetCompany returns a company with its applications
+func GetCompany(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	company, ok := findCompany(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var applications []models.Application
+	config.DB.Where("company_id = ? AND user_id = ?", company.ID, user.ID).
+		Order("applied_date DESC").Find(&applications)
+
+	c.JSON(http.StatusOK, gin.H{"company": company, "applications": applications})
+}
+
+// AutocompleteCompanies returns the user's companies whose name or an alias
+// starts with ?q=
+func AutocompleteCompanies(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	prefix := strings.ToLower(strings.TrimSpace(c.Query("q")))
+	limit := queryLimit(c, 10, 50)
+
+	var companies []models.Company
+	config.DB.Where("user_id = ?", user.ID).Order("name ASC").Find(&companies)
+
+	matches := []models.Company{}
+	for _, company := range companies {
+		for _, name := range services.CompanyNames(&company) {
+			if strings.HasPrefix(strings.ToLower(name), prefix) {
+				matches = append(matches, company)
+				break
+			}
+		}
+		if len(matches) == limit {
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, matches)
+}
+
+// SuggestCompanies returns existing companies similar to ?name=, so the
+// client can offer them before a near-duplicate is created
+func SuggestCompanies(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	suggestions, err := services.SuggestCompanies(config.DB, user.ID, c.Query("name"), 0, queryLimit(c, 5, 20))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load suggestions: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+func CreateCompany(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input companyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company data: " + err.Error()})
+		return
+	}
+
+	company := models.Company{UserID: user.ID}
+	if !applyCompanyInput(c, &company, &input) {
+		return
+	}
+
+	if err := config.DB.Create(&company).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create company: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, company)
+}
+
+// UpdateCompany edits a company. Renaming it renames its applications and
+// keeps the old name as an alias.
+func UpdateCompany(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	company, ok := findCompany(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var input companyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company data: " + err.Error()})
+		return
+	}
+
+	previousName := company.Name
+	if !applyCompanyInput(c, company, &input) {
+		return
+	}
+	renamed := company.Name != previousName
+	if renamed {
+		services.AddCompanyAlias(company, previousName)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(company).Error; err != nil {
+			return err
+		}
+		if !renamed {
+			return nil
+		}
+		return services.RenameCompanyApplications(tx, company)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update company: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, company)
+}
+
+// applyCompanyInput validates the input and copies it onto company,
+// responding with an error and returning false if it is invalid. A name or
+// alias may only resolve to one company.
+func applyCompanyInput(c *gin.Context, company *models.Company, input *companyInput) bool {
+	name := strings.TrimSpace(input.Name)
+	key := services.NormalizeCompanyName(name)
+	if key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Company name is required"})
+		return false
+	}
+
+	website := strings.TrimSpace(input.Website)
+	if website != "" {
+		parsed, err := url.Parse(website)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Website must be an http or https URL"})
+			return false
+		}
+	}
+
+	updated := *company
+	updated.Name = name
+	updated.NormalizedName = key
+	updated.Aliases = []string{}
+	for _, alias := range input.Aliases {
+		services.AddCompanyAlias(&updated, alias)
+	}
+
+	var others []models.Company
+	config.DB.Where("user_id = ? AND id <> ?", company.UserID, company.ID).Find(&others)
+	for _, name := range services.CompanyNames(&updated) {
+		for _, other := range others {
+			if services.CompanyHasName(&other, name) {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":      name + " already belongs to " + other.Name + ", merge the companies instead",
+					"company_id": other.ID,
+				})
+				return false
+			}
+		}
+	}
+
+	updated.Website = website
+	updated.Industry = strings.TrimSpace(input.Industry)
+	updated.Headquarters = strings.TrimSpace(input.Headquarters)
+	*company = updated
+	return true
+}
+
+// DeleteCompany deletes a company that no application uses anymore
+func DeleteCompany(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	company, ok := findCompany(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var count int64
+	config.DB.Unscoped().Model(&models.Application{}).Where("company_id = ?", company.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Company still has applications, merge it into another company instead"})
+		return
+	}
+
+	if err := config.DB.Delete(company).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete company: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Company deleted successfully"})
+}
+
+// MergeCompanies merges duplicate companies into the one in the URL
+func MergeCompanies(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	target, ok := findCompany(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		SourceIDs []uint `json:"source_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid merge data: " + err.Error()})
+		return
+	}
+
+	sourceIDs := uniqueIDs(input.SourceIDs)
+	for _, id := range sourceIDs {
+		if id == target.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A company can't be merged into itself"})
+			return
+		}
+	}
+
+	var sources []models.Company
+	config.DB.Where("id IN ? AND user_id = ?", sourceIDs, user.ID).Order("id ASC").Find(&sources)
+	if len(sources) != len(sourceIDs) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	var moved int
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		moved, err = services.MergeCompanies(tx, target, sources)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge companies: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"company":              target,
+		"merged":               len(sources),
+		"applications_updated": moved,
+	})
+}
+
+// linkApplicationCompany links the application to a company as part of tx,
+// either the one chosen with the company_id form field or the one its
+// company name resolves to. When that creates a new company, similar
+// existing ones are attached as suggestions.
+func linkApplicationCompany(tx *gorm.DB, app *models.Application, chosen *models.Company) error {
+	if chosen != nil {
+		app.CompanyID = &chosen.ID
+		app.Company = chosen.Name
+		return nil
+	}
+
+	company, created, err := services.LinkCompany(tx, app)
+	if err != nil || !created {
+		return err
+	}
+	app.CompanySuggestions, err = services.SuggestCompanies(tx, app.UserID, company.Name, company.ID, 5)
+	return err
+}
+
+// companyFromForm loads the company picked with the company_id form field,
+// responding with an error and returning false if it isn't the user's
+func companyFromForm(c *gin.Context, userID uint) (*models.Company, bool) {
+	value := c.PostForm("company_id")
+	if value == "" {
+		return nil, true
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company_id"})
+		return nil, false
+	}
+
+	var company models.Company
+	if err := config.DB.Where("id = ? AND user_id = ?", id, userID).First(&company).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return nil, false
+	}
+	return &company, true
+}
+
+// queryLimit reads ?limit=, falling back to def and capped at max
+func queryLimit(c *gin.Context, def, max int) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		return def
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
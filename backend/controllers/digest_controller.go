@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"html"
+	"net/http"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+func GetDigestSettings(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"weekly_digest": user.WeeklyDigest,
+		"timezone":      user.Timezone,
+	})
+}
+
+func UpdateDigestSettings(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input struct {
+		WeeklyDigest *bool   `json:"weekly_digest"`
+		Timezone     *string `json:"timezone"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid digest settings: " + err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.WeeklyDigest != nil {
+		updates["weekly_digest"] = *input.WeeklyDigest
+		user.WeeklyDigest = *input.WeeklyDigest
+	}
+	if input.Timezone != nil {
+		if _, err := time.LoadLocation(*input.Timezone); err != nil || *input.Timezone == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown timezone"})
+			return
+		}
+		updates["timezone"] = *input.Timezone
+		user.Timezone = *input.Timezone
+	}
+
+	if len(updates) > 0 {
+		if err := config.DB.Model(&models.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update digest settings: " + err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"weekly_digest": user.WeeklyDigest,
+		"timezone":      user.Timezone,
+	})
+}
+
+// PreviewDigest renders the current user's digest for the past week without sending it
+func PreviewDigest(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	digest, err := services.BuildDigest(config.DB, user, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build digest: " + err.Error()})
+		return
+	}
+
+	body, err := services.RenderDigest(digest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render digest: " + err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(body))
+}
+
+// UnsubscribeDigest handles the signed link in digest emails. GET only shows
+// a confirmation form, as link scanners and mail clients open links on their
+// own; POST, from the form or an RFC 8058 one-click unsubscribe, turns the
+// digest off.
+func UnsubscribeDigest(c *gin.Context) {
+	userID := c.Query("user")
+	if userID == "" || !services.VerifyDigestUnsubscribe(userID, c.Query("sig")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unsubscribe link"})
+		return
+	}
+
+	if c.Request.Method == http.MethodGet {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(
+			"<h2>Unsubscribe from the weekly digest?</h2>"+
+				"<form method=\"post\" action=\""+html.EscapeString(c.Request.URL.RequestURI())+"\">"+
+				"<button type=\"submit\">Unsubscribe</button></form>",
+		))
+		return
+	}
+
+	if err := config.DB.Model(&models.User{}).Where("id = ?", userID).Update("weekly_digest", false).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unsubscribe"})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(
+		"<h2>You've been unsubscribed</h2><p>You won't receive the weekly Internship Hub digest anymore. You can turn it back on from your profile settings.</p>",
+	))
+}
@@ -0,0 +1,406 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type emailRuleInput struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Field    string `json:"field"` // Defaults to any
+	Pattern  string `json:"pattern" binding:"required,max=500"`
+	Category string `json:"category" binding:"required"`
+	Priority int    `json:"priority"`
+	Enabled  *bool  `json:"enabled"` // Defaults to true
+}
+
+// findInboundEmail loads one of the current user's inbound emails, responding 404 if it doesn't exist
+func findInboundEmail(c *gin.Context, userID uint) (*models.InboundEmail, bool) {
+	var email models.InboundEmail
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&email).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Email not found"})
+		return nil, false
+	}
+	return &email, true
+}
+
+// readUploadedEmail parses the raw message sent as the request body, or as
+// the file form field of a multipart form, responding 400 if it can't
+func readUploadedEmail(c *gin.Context) (*services.ParsedEmail, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 10<<20)
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, _, err := c.Request.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email file is required (max 10MB)"})
+			return nil, false
+		}
+		defer file.Close()
+		body = file
+	}
+
+	email, err := services.ParseEmail(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read email: " + err.Error()})
+		return nil, false
+	}
+	return email, true
+}
+
+// UploadEmail ingests a raw RFC 822 message (an .eml file), matching it to
+// an application and proposing the status change it implies. The status
+// is changed right away if the user has auto-apply on. Uploading the same
+// message again returns the email ingested the first time.
+func UploadEmail(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	parsed, ok := readUploadedEmail(c)
+	if !ok {
+		return
+	}
+
+	email, created, err := services.IngestEmail(user.ID, parsed, models.InboundSourceUpload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to ingest email: " + err.Error()})
+		return
+	}
+
+	if !created {
+		c.JSON(http.StatusOK, email)
+		return
+	}
+	c.JSON(http.StatusCreated, email)
+}
+
+// PreviewEmail shows how an uploaded message would be classified and
+// matched, without saving it
+func PreviewEmail(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	parsed, ok := readUploadedEmail(c)
+	if !ok {
+		return
+	}
+
+	settings, err := services.GetEmailIngestionSettings(config.DB, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load email settings: " + err.Error()})
+		return
+	}
+	email, err := services.AnalyzeEmail(config.DB, settings, parsed, models.InboundSourceUpload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze email: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, email)
+}
+
+// GetInboundEmails lists the user's ingested emails, newest first.
+// ?state=proposed only lists the ones waiting for a decision.
+func GetInboundEmails(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := config.DB.Where("user_id = ?", user.ID)
+	if state := c.Query("state"); state != "" {
+		query = query.Where("state = ?", state)
+	}
+
+	var emails []models.InboundEmail
+	query.Preload("Application").Order("received_at DESC").Limit(200).Find(&emails)
+	c.JSON(http.StatusOK, emails)
+}
+
+// ApplyInboundEmail makes the status change an email proposes. The body
+// can pick another application or status, e.g. for an unmatched email:
+// {"application_id": 3, "status": 2}.
+func ApplyInboundEmail(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	email, ok := findInboundEmail(c, user.ID)
+	if !ok {
+		return
+	}
+	if email.State == models.InboundApplied {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email was already applied"})
+		return
+	}
+
+	var input struct {
+		ApplicationID *uint                     `json:"application_id"`
+		Status        *models.ApplicationStatus `json:"status"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+			return
+		}
+	}
+
+	applicationID := email.ApplicationID
+	if input.ApplicationID != nil {
+		applicationID = input.ApplicationID
+	}
+	if input.Status != nil {
+		if *input.Status < models.StatusApplied || *input.Status > models.StatusRejected {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status value"})
+			return
+		}
+		email.ProposedStatus = input.Status
+	} else if email.ProposedStatus == nil {
+		if status, ok := services.EmailCategoryStatus(email.Category); ok {
+			email.ProposedStatus = &status
+		}
+	}
+	if applicationID == nil || email.ProposedStatus == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "application_id and status are required for this email"})
+		return
+	}
+
+	var app models.Application
+	if err := config.DB.Where("id = ? AND user_id = ?", *applicationID, user.ID).First(&app).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(email).Update("proposed_status", *email.ProposedStatus).Error; err != nil {
+			return err
+		}
+		return services.ApplyInboundEmail(tx, email, &app)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply email: " + err.Error()})
+		return
+	}
+
+	email.Application = &app
+	c.JSON(http.StatusOK, email)
+}
+
+// DismissInboundEmail leaves the application as it is
+func DismissInboundEmail(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	email, ok := findInboundEmail(c, user.ID)
+	if !ok {
+		return
+	}
+	if email.State == models.InboundApplied {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email was already applied"})
+		return
+	}
+
+	if err := config.DB.Model(email).Update("state", models.InboundDismissed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to dismiss email: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, email)
+}
+
+// GetEmailRules lists the user's classification rules in the order they
+// are tried, along with the built-in ones
+func GetEmailRules(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var rules []models.EmailRule
+	config.DB.Where("user_id = ?", user.ID).Order("priority ASC, id ASC").Find(&rules)
+	c.JSON(http.StatusOK, gin.H{"rules": rules, "default_rules": services.DefaultEmailRules})
+}
+
+func CreateEmailRule(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input emailRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule data: " + err.Error()})
+		return
+	}
+
+	rule := models.EmailRule{UserID: user.ID}
+	if !applyEmailRuleInput(c, &rule, &input) {
+		return
+	}
+
+	if err := config.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create rule: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func UpdateEmailRule(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var rule models.EmailRule
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	}
+
+	var input emailRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule data: " + err.Error()})
+		return
+	}
+	if !applyEmailRuleInput(c, &rule, &input) {
+		return
+	}
+
+	if err := config.DB.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update rule: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func DeleteEmailRule(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).Delete(&models.EmailRule{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete rule: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rule deleted successfully"})
+}
+
+// applyEmailRuleInput copies the input onto the rule and validates it,
+// responding 400 and returning false if it isn't valid
+func applyEmailRuleInput(c *gin.Context, rule *models.EmailRule, input *emailRuleInput) bool {
+	rule.Name = strings.TrimSpace(input.Name)
+	rule.Field = input.Field
+	if rule.Field == "" {
+		rule.Field = models.RuleFieldAny
+	}
+	rule.Pattern = input.Pattern
+	rule.Category = input.Category
+	rule.Priority = input.Priority
+	rule.Enabled = input.Enabled == nil || *input.Enabled
+
+	if err := services.ValidateEmailRule(rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// emailIngestionResponse adds the address to forward emails to, when the
+// server polls a mailbox
+func emailIngestionResponse(settings *models.EmailIngestionSettings) gin.H {
+	return gin.H{
+		"auto_apply":        settings.AutoApply,
+		"use_default_rules": settings.UseDefaultRules,
+		"forward_to":        services.IngestionAddress(settings),
+	}
+}
+
+func GetEmailIngestionSettings(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	settings, err := services.GetEmailIngestionSettings(config.DB, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load email settings: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, emailIngestionResponse(settings))
+}
+
+func UpdateEmailIngestionSettings(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input struct {
+		AutoApply       *bool `json:"auto_apply"`
+		UseDefaultRules *bool `json:"use_default_rules"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email settings: " + err.Error()})
+		return
+	}
+
+	settings, err := services.GetEmailIngestionSettings(config.DB, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load email settings: " + err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.AutoApply != nil {
+		updates["auto_apply"] = *input.AutoApply
+		settings.AutoApply = *input.AutoApply
+	}
+	if input.UseDefaultRules != nil {
+		updates["use_default_rules"] = *input.UseDefaultRules
+		settings.UseDefaultRules = *input.UseDefaultRules
+	}
+
+	if len(updates) > 0 {
+		if err := config.DB.Model(settings).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update email settings: " + err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, emailIngestionResponse(settings))
+}
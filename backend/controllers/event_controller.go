@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateStreamTicket issues a ticket for opening the event stream with the
+// EventSource API, as GET /events/stream?ticket=
+func CreateStreamTicket(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var token *models.PersonalAccessToken
+	if value, ok := c.Get("accessToken"); ok {
+		token = value.(*models.PersonalAccessToken)
+	}
+
+	ticket, expiresAt := services.NewStreamTicket(user, token)
+	c.JSON(http.StatusOK, gin.H{"ticket": ticket, "expires_at": expiresAt})
+}
+
+// StreamEvents streams the current user's application created/updated/deleted
+// events as Server-Sent Events. Clients that reconnect with Last-Event-ID
+// (or ?last_event_id=) first receive the events they missed.
+func StreamEvents(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// Subscribe before loading missed events so nothing slips through the gap
+	events, unsubscribe := services.SubscribeApplicationEvents(user.ID)
+	defer unsubscribe()
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var missed []models.ApplicationEvent
+	if lastEventID != "" {
+		id, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+		if missed, err = services.ApplicationEventsSince(user.ID, uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load missed events"})
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	// Live events may also be among the missed ones. IDs aren't compared
+	// otherwise, as events can commit out of ID order.
+	replayed := make(map[uint]bool, len(missed))
+	fmt.Fprint(c.Writer, "retry: 5000\n\n")
+	for _, event := range missed {
+		writeEvent(c.Writer, event)
+		replayed[event.ID] = true
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(25 * time.Second)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			if !replayed[event.ID] {
+				writeEvent(w, event)
+			}
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		return true
+	})
+}
+
+func writeEvent(w io.Writer, event models.ApplicationEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Payload)
+}
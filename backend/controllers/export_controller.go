@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"archive/zip"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateExport exports the user's data as a ZIP archive. Small accounts get
+// the archive in the response; larger ones (or ?async=true) are exported in
+// the background and the user is emailed a download link.
+func CreateExport(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var count int64
+	config.DB.Model(&models.Application{}).Where("user_id = ?", user.ID).Count(&count)
+
+	if c.Query("async") != "true" && count <= int64(config.GetEnvInt("EXPORT_SYNC_MAX_APPLICATIONS", 50)) {
+		filename := fmt.Sprintf("internship-hub-export-%s.zip", time.Now().Format("2006-01-02"))
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Status(http.StatusOK)
+
+		if err := services.WriteUserExport(config.DB, c.Writer, user.ID); err != nil {
+			// Headers are already sent, all we can do is cut the archive short
+			log.Printf("Exports: export for user %d failed: %v", user.ID, err)
+			c.Abort()
+		}
+		return
+	}
+
+	var pending int64
+	config.DB.Model(&models.DataExport{}).Where("user_id = ? AND status = ?", user.ID, models.ExportPending).Count(&pending)
+	if pending > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "An export is already being prepared"})
+		return
+	}
+
+	export := models.DataExport{UserID: user.ID, Status: models.ExportPending}
+	if err := config.DB.Create(&export).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start export: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Your export is being prepared. We'll email you a download link when it's ready.",
+		"export":  export,
+	})
+}
+
+func GetExports(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var exports []models.DataExport
+	config.DB.Where("user_id = ?", user.ID).Order("created_at DESC").Find(&exports)
+	c.JSON(http.StatusOK, exports)
+}
+
+func DownloadExport(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var export models.DataExport
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&export).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+
+	sendExportFile(c, &export)
+}
+
+// DownloadExportLink serves the signed download link from the export email
+func DownloadExportLink(c *gin.Context) {
+	exportID := c.Query("export")
+	if exportID == "" || !services.VerifyExportDownload(exportID, c.Query("sig")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid download link"})
+		return
+	}
+
+	var export models.DataExport
+	if err := config.DB.First(&export, exportID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This export has expired"})
+		return
+	}
+
+	sendExportFile(c, &export)
+}
+
+func sendExportFile(c *gin.Context, export *models.DataExport) {
+	if export.Status != models.ExportReady {
+		c.JSON(http.StatusConflict, gin.H{"error": "Export is not ready", "status": export.Status})
+		return
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This export has expired"})
+		return
+	}
+
+	c.FileAttachment(export.FilePath, fmt.Sprintf("internship-hub-export-%s.zip", export.CompletedAt.Format("2006-01-02")))
+}
+
+// RestoreExport imports an archive made by CreateExport. The strategy form
+// field decides what happens to applications that already exist: skip
+// (default), update, or replace to delete all current applications first.
+func RestoreExport(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	maxSize := int64(config.GetEnvInt("IMPORT_MAX_ARCHIVE_MB", 100)) << 20
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+
+	file, fileHeader, err := c.Request.FormFile("archive")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Export archive is required (max " + strconv.FormatInt(maxSize>>20, 10) + "MB)"})
+		return
+	}
+	defer file.Close()
+
+	strategy := c.DefaultPostForm("strategy", services.RestoreSkip)
+	if strategy != services.RestoreSkip && strategy != services.RestoreUpdate && strategy != services.RestoreReplace {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Strategy must be skip, update or replace"})
+		return
+	}
+
+	archive, err := zip.NewReader(file, fileHeader.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Export archive must be a ZIP file"})
+		return
+	}
+
+	manifest, data, err := services.ReadExportArchive(archive)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export archive: " + err.Error()})
+		return
+	}
+
+	report, err := services.RestoreUserData(user.ID, archive, data, strategy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore export: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exported_at": manifest.ExportedAt,
+		"report":      report,
+	})
+}
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetImportFormats lists the formats applications can be imported from
+func GetImportFormats(c *gin.Context) {
+	formats := []gin.H{}
+	for _, importer := range services.Importers() {
+		formats = append(formats, gin.H{
+			"format":      importer.Format(),
+			"description": importer.Description(),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"formats": formats, "fields": services.ImportFields})
+}
+
+// PreviewImport shows what importing a file would do without saving
+// anything. It takes the same form as ImportApplications.
+func PreviewImport(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	preview, ok := readImportFile(c, user.ID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// ImportApplications imports a tracker export uploaded as the file form
+// field. The format is detected from the header unless the format field
+// names one, or the mapping field maps fields to columns as JSON, e.g.
+// {"company": "Employer", "position": "Job"}. New rows become applications,
+// or wishlist items for postings not applied to yet; duplicates and
+// invalid rows are skipped.
+func ImportApplications(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	preview, ok := readImportFile(c, user.ID)
+	if !ok {
+		return
+	}
+
+	today := time.Now().In(services.UserLocation(user))
+	created := map[string]int{"applications": 0, "wishlist": 0}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range preview.Rows {
+			row := &preview.Rows[i]
+			if row.Result != services.ImportResultNew {
+				continue
+			}
+
+			if row.Wishlist {
+				item := models.WishlistItem{
+					UserID:     user.ID,
+					Company:    row.Company,
+					Position:   row.Position,
+					Location:   row.Location,
+					Term:       row.Term,
+					PostingURL: row.PostingURL,
+					Priority:   models.PriorityMedium,
+					Note:       row.Note,
+				}
+				if err := tx.Create(&item).Error; err != nil {
+					return err
+				}
+				row.WishlistItemID = &item.ID
+				created["wishlist"]++
+				continue
+			}
+
+			app := models.Application{
+				Company:     row.Company,
+				Position:    row.Position,
+				Status:      row.Status,
+				Location:    row.Location,
+				AppliedDate: time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC),
+				Term:        row.Term,
+				Note:        row.Note,
+				PostingURL:  row.PostingURL,
+				UserID:      user.ID,
+			}
+			if row.AppliedDate != nil {
+				app.AppliedDate = *row.AppliedDate
+			}
+			services.ApplyLocation(&app)
+			if _, _, err := services.LinkCompany(tx, &app); err != nil {
+				return err
+			}
+			if err := linkApplicationTerm(tx, &app, user); err != nil {
+				return err
+			}
+			if err := tx.Create(&app).Error; err != nil {
+				return err
+			}
+			if err := services.PublishApplicationEvent(tx, models.EventApplicationCreated, &app); err != nil {
+				return err
+			}
+			row.ApplicationID = &app.ID
+			created["applications"]++
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import applications: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"format":  preview.Format,
+		"created": created,
+		"counts":  preview.Counts,
+		"rows":    preview.Rows,
+	})
+}
+
+// readImportFile reads and diffs the uploaded file with the importer the
+// form asks for, responding with an error and returning false if it can't
+func readImportFile(c *gin.Context, userID uint) (*services.ImportPreview, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 5<<20)
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV file is required (max 5MB)"})
+		return nil, false
+	}
+	defer file.Close()
+
+	var importer services.Importer
+	if value := c.PostForm("mapping"); value != "" {
+		var mapping map[string]string
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Mapping must be a JSON object of fields to columns"})
+			return nil, false
+		}
+		if importer, err = services.NewMappedImporter(mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping: " + err.Error()})
+			return nil, false
+		}
+	} else if format := c.PostForm("format"); format != "" {
+		if importer = services.FindImporter(format); importer == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown format " + format})
+			return nil, false
+		}
+	}
+
+	preview, err := services.ReadImport(file, importer, config.GetEnvInt("IMPORT_MAX_ROWS", 2000))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file: " + err.Error()})
+		return nil, false
+	}
+	if err := services.DiffImport(config.DB, userID, preview); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare rows: " + err.Error()})
+		return nil, false
+	}
+	return preview, true
+}
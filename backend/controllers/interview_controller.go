@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type interviewInput struct {
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+	Kind        string    `json:"kind"`
+	Location    string    `json:"location"`
+	Note        string    `json:"note" binding:"max=1048"`
+}
+
+// GetInterviews lists the user's interviews, soonest first.
+// Use ?upcoming=true to only include interviews that haven't happened yet.
+func GetInterviews(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := config.DB.Preload("Application").Scopes(models.WithoutTrashedApplications).Where("user_id = ?", user.ID)
+	if c.Query("upcoming") == "true" {
+		query = query.Where("scheduled_at >= ?", time.Now())
+	}
+
+	var interviews []models.Interview
+	query.Order("scheduled_at ASC").Find(&interviews)
+	c.JSON(http.StatusOK, interviews)
+}
+
+func CreateInterview(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var app models.Application
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&app).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	var input interviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interview data: " + err.Error()})
+		return
+	}
+
+	interview := models.Interview{
+		ApplicationID: app.ID,
+		UserID:        user.ID,
+		ScheduledAt:   input.ScheduledAt,
+		Kind:          input.Kind,
+		Location:      input.Location,
+		Note:          input.Note,
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&interview).Error; err != nil {
+			return err
+		}
+		return services.EnqueueWebhookEvent(tx, user.ID, models.WebhookInterviewScheduled, gin.H{
+			"interview":   interview,
+			"application": app,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create interview: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, interview)
+}
+
+func UpdateInterview(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var interview models.Interview
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&interview).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+
+	var input interviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interview data: " + err.Error()})
+		return
+	}
+
+	if !interview.ScheduledAt.Equal(input.ScheduledAt) {
+		interview.NotifiedAt = nil // Rescheduled, remind the user again
+	}
+	interview.ScheduledAt = input.ScheduledAt
+	interview.Kind = input.Kind
+	interview.Location = input.Location
+	interview.Note = input.Note
+
+	if err := config.DB.Save(&interview).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update interview: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, interview)
+}
+
+func DeleteInterview(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).Delete(&models.Interview{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete interview: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Interview deleted successfully"})
+}
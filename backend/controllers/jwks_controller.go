@@ -0,0 +1,15 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS publishes the public keys session tokens are signed with, so other
+// services can verify them. Keys stay listed until their file is removed.
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": services.PublicJWKS()})
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ImportListings reads a community internship list uploaded as the
+// markdown file form field, compares it to the user's wishlist and
+// applications, and adds the new postings to the wishlist. Form fields:
+//   - dry_run=true only reports what would be added
+//   - role=intern only imports roles that contain it
+//   - posted_since=2025-09-01 skips older postings
+//   - include_closed=true also adds postings marked closed
+//   - term and priority are set on the added items
+func ImportListings(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 5<<20)
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Markdown file is required (max 5MB)"})
+		return
+	}
+	defer file.Close()
+
+	dryRun := c.PostForm("dry_run") == "true"
+	includeClosed := c.PostForm("include_closed") == "true"
+	role := strings.ToLower(strings.TrimSpace(c.PostForm("role")))
+
+	var postedSince *time.Time
+	if value := c.PostForm("posted_since"); value != "" {
+		since, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "posted_since must be a date like 2025-09-01"})
+			return
+		}
+		postedSince = &since
+	}
+
+	priority, ok := wishlistPriority(c.PostForm("priority"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Priority must be low, medium or high"})
+		return
+	}
+	term := wishlistTerm(c.PostForm("term"))
+
+	listings, err := services.ParseMarkdownListings(file, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read markdown file: " + err.Error()})
+		return
+	}
+	if len(listings) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No listing table found, it needs at least Company and Role columns"})
+		return
+	}
+
+	filtered := listings[:0]
+	for _, listing := range listings {
+		if role != "" && !strings.Contains(strings.ToLower(listing.Role), role) {
+			continue
+		}
+		if postedSince != nil && listing.DatePosted != nil && listing.DatePosted.Before(*postedSince) {
+			continue
+		}
+		filtered = append(filtered, listing)
+	}
+
+	diffs, err := services.DiffListings(config.DB, user.ID, filtered)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare listings: " + err.Error()})
+		return
+	}
+
+	counts := map[string]int{"added": 0}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range diffs {
+			diff := &diffs[i]
+			counts[diff.Status]++
+			add := diff.Status == services.ListingNew || (includeClosed && diff.Status == services.ListingClosed)
+			if !add || dryRun {
+				continue
+			}
+
+			item := models.WishlistItem{
+				UserID:     user.ID,
+				Company:    diff.Company,
+				Position:   diff.Role,
+				Location:   diff.Location,
+				Term:       term,
+				PostingURL: diff.URL,
+				DatePosted: diff.DatePosted,
+				Priority:   priority,
+			}
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+			diff.WishlistItemID = &item.ID
+			counts["added"]++
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import listings: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dry_run":  dryRun,
+		"counts":   counts,
+		"listings": diffs,
+	})
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetNotifications returns the newest notifications in the user's inbox.
+// Use ?unread=true to only list unread ones and ?limit=N to page (max 200).
+func GetNotifications(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	query := config.DB.Where("user_id = ?", user.ID)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	query.Order("created_at DESC").Limit(limit).Find(&notifications)
+
+	var unreadCount int64
+	config.DB.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", user.ID).Count(&unreadCount)
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"unread_count":  unreadCount,
+	})
+}
+
+func MarkNotificationRead(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var notification models.Notification
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&notification).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+		if err := config.DB.Save(&notification).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification: " + err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+func MarkAllNotificationsRead(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", user.ID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications: " + result.Error.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": result.RowsAffected})
+}
+
+type notificationSettingsResponse struct {
+	models.NotificationSettings
+	Timezone string `json:"timezone"`
+}
+
+func GetNotificationSettings(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	settings, err := services.GetNotificationSettings(config.DB, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load notification settings: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationSettingsResponse{settings, user.Timezone})
+}
+
+func UpdateNotificationSettings(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input struct {
+		StatusChanged     models.NotificationChannels `json:"status_changed"`
+		ReminderDue       models.NotificationChannels `json:"reminder_due"`
+		InterviewUpcoming models.NotificationChannels `json:"interview_upcoming"`
+		QuietHoursStart   string                      `json:"quiet_hours_start"`
+		QuietHoursEnd     string                      `json:"quiet_hours_end"`
+		Timezone          *string                     `json:"timezone"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification settings: " + err.Error()})
+		return
+	}
+
+	if !services.ValidClock(input.QuietHoursStart) || !services.ValidClock(input.QuietHoursEnd) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quiet hours must use HH:MM format"})
+		return
+	}
+	if input.Timezone != nil {
+		if _, err := time.LoadLocation(*input.Timezone); err != nil || *input.Timezone == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown timezone"})
+			return
+		}
+		user.Timezone = *input.Timezone
+	}
+
+	settings, err := services.GetNotificationSettings(config.DB, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load notification settings: " + err.Error()})
+		return
+	}
+
+	settings.StatusChanged = input.StatusChanged
+	settings.ReminderDue = input.ReminderDue
+	settings.InterviewUpcoming = input.InterviewUpcoming
+	settings.QuietHoursStart = input.QuietHoursStart
+	settings.QuietHoursEnd = input.QuietHoursEnd
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&settings).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", user.ID).Update("timezone", user.Timezone).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification settings: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationSettingsResponse{settings, user.Timezone})
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+type offerInput struct {
+	PayAmount         float64    `json:"pay_amount" binding:"required,gt=0"`
+	PayPeriod         string     `json:"pay_period" binding:"required"`
+	Currency          string     `json:"currency"` // Defaults to OFFER_BASE_CURRENCY
+	HousingStipend    float64    `json:"housing_stipend" binding:"gte=0"`
+	RelocationStipend float64    `json:"relocation_stipend" binding:"gte=0"`
+	StartDate         *time.Time `json:"start_date"`
+	EndDate           *time.Time `json:"end_date"`
+	Location          string     `json:"location"`
+	DecisionDeadline  *time.Time `json:"decision_deadline"`
+	ReturnOffer       string     `json:"return_offer"`
+	BenefitsNotes     string     `json:"benefits_notes" binding:"max=2048"`
+}
+
+func (input *offerInput) validate() string {
+	if !services.IsPayPeriod(input.PayPeriod) {
+		return "Pay period must be hourly, monthly or annual"
+	}
+	if input.Currency != "" && len(input.Currency) != 3 {
+		return "Currency must be a three-letter code like USD"
+	}
+	if input.StartDate != nil && input.EndDate != nil && !input.EndDate.After(*input.StartDate) {
+		return "End date must be after the start date"
+	}
+	switch input.ReturnOffer {
+	case "", models.ReturnOfferUnknown, models.ReturnOfferLikely, models.ReturnOfferPossible, models.ReturnOfferUnlikely:
+	default:
+		return "Return offer must be unknown, likely, possible or unlikely"
+	}
+	return ""
+}
+
+// GetOffers lists the user's offers with their applications, nearest
+// decision deadline first
+func GetOffers(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var offers []models.Offer
+	config.DB.Preload("Application").Scopes(models.WithoutTrashedApplications).
+		Where("user_id = ?", user.ID).
+		Order("decision_deadline IS NULL, decision_deadline ASC, created_at ASC").
+		Find(&offers)
+
+	c.JSON(http.StatusOK, offers)
+}
+
+func GetOffer(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var offer models.Offer
+	if err := config.DB.Where("application_id = ? AND user_id = ?", c.Param("id"), user.ID).First(&offer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// SaveOffer records the offer for an application, replacing the one
+// recorded before
+func SaveOffer(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var app models.Application
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&app).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	var input offerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offer data: " + err.Error()})
+		return
+	}
+	if msg := input.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	offer := models.Offer{ApplicationID: app.ID, UserID: user.ID}
+	config.DB.Where("application_id = ?", app.ID).First(&offer)
+	created := offer.ID == 0
+
+	offer.PayAmount = input.PayAmount
+	offer.PayPeriod = input.PayPeriod
+	offer.Currency = strings.ToUpper(input.Currency)
+	if offer.Currency == "" {
+		offer.Currency = config.GetOfferRates().BaseCurrency
+	}
+	offer.HousingStipend = input.HousingStipend
+	offer.RelocationStipend = input.RelocationStipend
+	offer.StartDate = input.StartDate
+	offer.EndDate = input.EndDate
+	offer.Location = input.Location
+	offer.DecisionDeadline = input.DecisionDeadline
+	offer.ReturnOffer = input.ReturnOffer
+	if offer.ReturnOffer == "" {
+		offer.ReturnOffer = models.ReturnOfferUnknown
+	}
+	offer.BenefitsNotes = input.BenefitsNotes
+
+	if err := config.DB.Save(&offer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save offer: " + err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, offer)
+}
+
+func DeleteOffer(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result := config.DB.Where("application_id = ? AND user_id = ?", c.Param("id"), user.ID).Delete(&models.Offer{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete offer: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Offer deleted successfully"})
+}
+
+// CompareOffers compares the user's offers (or ?ids=1,2) side by side, with
+// pay converted to ?period= (default monthly) and ?currency= (default
+// OFFER_BASE_CURRENCY) using the OFFER_EXCHANGE_RATES rate table
+func CompareOffers(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	rates := config.GetOfferRates()
+	currency := strings.ToUpper(c.DefaultQuery("currency", rates.BaseCurrency))
+	if _, ok := rates.Rates[currency]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No exchange rate for " + currency})
+		return
+	}
+	period := c.DefaultQuery("period", models.PayMonthly)
+	if !services.IsPayPeriod(period) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Period must be hourly, monthly or annual"})
+		return
+	}
+
+	query := config.DB.Preload("Application").Scopes(models.WithoutTrashedApplications).Where("user_id = ?", user.ID)
+	if ids := parseIDList(c.Query("ids")); len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	var offers []models.Offer
+	if err := query.Find(&offers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load offers: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, services.CompareOffers(offers, rates, currency, period))
+}
@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetOIDCProviders lists the SSO providers configured for the login page
+func GetOIDCProviders(c *gin.Context) {
+	providers := []gin.H{}
+	for _, provider := range config.GetOIDCProviders() {
+		providers = append(providers, gin.H{
+			"name":         provider.Name,
+			"display_name": provider.DisplayName,
+			"login_url":    "/auth/oidc/" + provider.Name + "/login",
+		})
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i]["name"].(string) < providers[j]["name"].(string)
+	})
+
+	c.JSON(http.StatusOK, providers)
+}
+
+// oidcStateCookie ties a login's state to the browser that started it, so
+// a callback URL sent to someone else can't sign them in
+const oidcStateCookie = "oidc_state"
+
+// setOIDCStateCookie sets the state cookie for the callback, or clears it
+// when state is empty
+func setOIDCStateCookie(c *gin.Context, state string) {
+	maxAge := int((10 * time.Minute).Seconds())
+	if state == "" {
+		maxAge = -1
+	}
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, maxAge, "/auth/oidc", "", secure, true)
+}
+
+// OIDCLogin starts an authorization code + PKCE login by redirecting the
+// browser to the provider
+func OIDCLogin(c *gin.Context) {
+	provider, ok := config.GetOIDCProviders()[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown SSO provider"})
+		return
+	}
+
+	state, nonce := services.NewOIDCState()
+	verifier, challenge := services.NewPKCE()
+
+	authURL, err := services.OIDCAuthorizationURL(provider, state, nonce, challenge)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "SSO provider unavailable: " + err.Error()})
+		return
+	}
+
+	// Clean up abandoned logins while we're here
+	config.DB.Where("expires_at < ?", time.Now()).Delete(&models.OIDCLoginState{})
+
+	loginState := models.OIDCLoginState{
+		State:        state,
+		Provider:     provider.Name,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(10 * time.Minute),
+	}
+	if err := config.DB.Create(&loginState).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start SSO login"})
+		return
+	}
+
+	setOIDCStateCookie(c, state)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback finishes the login started by OIDCLogin and sends the browser
+// back to the frontend with a session token in the URL fragment
+func OIDCCallback(c *gin.Context) {
+	provider, ok := config.GetOIDCProviders()[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown SSO provider"})
+		return
+	}
+
+	if providerError := c.Query("error"); providerError != "" {
+		redirectSSOError(c, "Sign in was cancelled or denied: "+providerError)
+		return
+	}
+
+	// The state must come from this browser's login, and can only be used once
+	cookie, _ := c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "")
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(c.Query("state"))) != 1 {
+		redirectSSOError(c, "Sign in expired, please try again")
+		return
+	}
+
+	var loginState models.OIDCLoginState
+	err := config.DB.Where("state = ? AND provider = ? AND expires_at > ?", c.Query("state"), provider.Name, time.Now()).
+		First(&loginState).Error
+	if err != nil || c.Query("state") == "" {
+		redirectSSOError(c, "Sign in expired, please try again")
+		return
+	}
+	config.DB.Delete(&loginState)
+
+	claims, err := services.ExchangeOIDCCode(provider, c.Query("code"), loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Printf("SSO login with %s failed: %v", provider.Name, err)
+		redirectSSOError(c, "Could not verify your sign in with "+provider.DisplayName)
+		return
+	}
+
+	user, err := resolveOIDCUser(provider.Name, claims)
+	if err != nil {
+		redirectSSOError(c, err.Error())
+		return
+	}
+
+	token, err := generateAuthToken(user)
+	if err != nil {
+		redirectSSOError(c, "Failed to generate token")
+		return
+	}
+
+	c.Redirect(http.StatusFound, ssoRedirectURL()+"#token="+url.QueryEscape(token))
+}
+
+// resolveOIDCUser finds the user for an SSO login: by linked identity first,
+// then by verified email (linking the identity), otherwise a new account is created
+func resolveOIDCUser(providerName string, claims *services.OIDCClaims) (*models.User, error) {
+	var user models.User
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var identity models.UserIdentity
+		result := tx.Where("provider = ? AND subject = ?", providerName, claims.Subject).Limit(1).Find(&identity)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			identity.LastLoginAt = time.Now()
+			if err := tx.Save(&identity).Error; err != nil {
+				return err
+			}
+			return tx.First(&user, identity.UserID).Error
+		}
+
+		if claims.Email == "" {
+			return errors.New("Your SSO account has no email address")
+		}
+
+		result = tx.Where("LOWER(email) = LOWER(?)", claims.Email).Limit(1).Find(&user)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			// Only link to an existing account if the provider vouches for the address
+			if !claims.EmailVerified() {
+				return errors.New("Your SSO email address is not verified, so it can't be linked to your existing account")
+			}
+			// Anyone could have registered an unverified account with this
+			// address, so its password isn't the owner's. It's cleared, along
+			// with its sessions, before the owner gets the account.
+			if !user.IsVerified {
+				user.IsVerified = true
+				user.Password = ""
+				user.SessionVersion++
+				if err := tx.Model(&user).Updates(map[string]interface{}{
+					"is_verified":     true,
+					"password":        "",
+					"session_version": user.SessionVersion,
+				}).Error; err != nil {
+					return err
+				}
+			}
+		} else {
+			created, err := createSSOUser(tx, claims)
+			if err != nil {
+				return err
+			}
+			user = *created
+		}
+
+		return tx.Create(&models.UserIdentity{
+			UserID:      user.ID,
+			Provider:    providerName,
+			Subject:     claims.Subject,
+			Email:       claims.Email,
+			LastLoginAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+var usernameCleaner = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// createSSOUser creates the account for a new SSO user. The provider must
+// have verified the address, or the account would keep its real owner from
+// registering.
+func createSSOUser(tx *gorm.DB, claims *services.OIDCClaims) (*models.User, error) {
+	if !claims.EmailVerified() {
+		return nil, errors.New("Your SSO email address is not verified, please verify it with your provider first")
+	}
+
+	base := claims.PreferredUsername
+	if base == "" || strings.Contains(base, "@") {
+		base = strings.Split(claims.Email, "@")[0]
+	}
+	base = usernameCleaner.ReplaceAllString(base, "")
+	if base == "" {
+		base = "user"
+	}
+
+	// Find a free username, adding a number if needed
+	username := base
+	for i := 2; ; i++ {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("LOWER(username) = LOWER(?)", username).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			break
+		}
+		username = fmt.Sprintf("%s%d", base, i)
+	}
+
+	// SSO accounts have no password until they set one, see checkPassword
+	user := models.User{
+		Username:   username,
+		Email:      claims.Email,
+		IsVerified: true,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func ssoRedirectURL() string {
+	if redirect := os.Getenv("OIDC_FRONTEND_REDIRECT_URL"); redirect != "" {
+		return redirect
+	}
+	return os.Getenv("FRONTEND_URL") + "/login"
+}
+
+func redirectSSOError(c *gin.Context, message string) {
+	c.Redirect(http.StatusFound, ssoRedirectURL()+"?sso_error="+url.QueryEscape(message))
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPostingHTML caps the size of a captured page
+const maxPostingHTML = 2 << 20
+
+// CapturePosting turns a job posting page into a draft application that
+// isn't saved. The page is sent as JSON {"html", "url"}, or as a raw
+// text/html body with ?url=, e.g. by a bookmarklet. The client reviews the
+// draft and creates the application with POST /applications.
+func CapturePosting(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPostingHTML)
+
+	var input struct {
+		HTML string `json:"html" binding:"required"`
+		URL  string `json:"url"`
+	}
+	if strings.HasPrefix(c.ContentType(), "application/json") {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid posting data: " + err.Error()})
+			return
+		}
+	} else {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Request body too large (Max 2MB)"})
+			return
+		}
+		input.HTML, input.URL = string(body), c.Query("url")
+	}
+	if strings.TrimSpace(input.HTML) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Posting HTML is required"})
+		return
+	}
+
+	posting, err := services.ParsePostingHTML(strings.NewReader(input.HTML), input.URL)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to read posting: " + err.Error()})
+		return
+	}
+
+	app := models.Application{
+		Company:     posting.Company,
+		Position:    posting.Position,
+		Status:      models.StatusApplied,
+		Location:    posting.Location,
+		AppliedDate: time.Now(),
+		PostingURL:  posting.URL,
+		Description: posting.Description,
+		Deadline:    posting.Deadline,
+		UserID:      user.ID,
+	}
+	services.ApplyLocation(&app)
+
+	// Titles like "Software Engineer Intern, Summer 2026" name the term,
+	// otherwise the draft gets the active term as a new application would
+	if season, year, ok := services.ParseTerm(app.Position); ok {
+		app.Term = services.TermName(season, year)
+	} else if user.ActiveTermID != nil {
+		var term models.Term
+		if err := config.DB.First(&term, *user.ActiveTermID).Error; err == nil {
+			app.Term = term.Name
+		}
+	}
+
+	// Use the known company's name, or suggest similar ones
+	company, err := services.FindCompany(config.DB, user.ID, app.Company)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to look up company: " + err.Error()})
+		return
+	}
+	if company != nil {
+		app.CompanyID = &company.ID
+		app.Company = company.Name
+	} else if app.Company != "" {
+		if app.CompanySuggestions, err = services.SuggestCompanies(config.DB, user.ID, app.Company, 0, 3); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suggest companies: " + err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"application": app,
+		"source":      posting.Source,
+	})
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+type reminderInput struct {
+	Title         string    `json:"title" binding:"required"`
+	DueAt         time.Time `json:"due_at" binding:"required"`
+	ApplicationID *uint     `json:"application_id"`
+	Completed     bool      `json:"completed"`
+}
+
+// GetReminders lists the user's reminders by due date.
+// Use ?pending=true to hide completed reminders.
+func GetReminders(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := config.DB.Preload("Application").Scopes(models.WithoutTrashedApplications).Where("user_id = ?", user.ID)
+	if c.Query("pending") == "true" {
+		query = query.Where("completed_at IS NULL")
+	}
+
+	var reminders []models.Reminder
+	query.Order("due_at ASC").Find(&reminders)
+	c.JSON(http.StatusOK, reminders)
+}
+
+func CreateReminder(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input reminderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reminder data: " + err.Error()})
+		return
+	}
+
+	if !ownsApplication(user.ID, input.ApplicationID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	reminder := models.Reminder{
+		UserID:        user.ID,
+		ApplicationID: input.ApplicationID,
+		Title:         input.Title,
+		DueAt:         input.DueAt,
+	}
+	if input.Completed {
+		now := time.Now()
+		reminder.CompletedAt = &now
+	}
+
+	if err := config.DB.Create(&reminder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reminder: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reminder)
+}
+
+func UpdateReminder(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var reminder models.Reminder
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&reminder).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+		return
+	}
+
+	var input reminderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reminder data: " + err.Error()})
+		return
+	}
+
+	if !ownsApplication(user.ID, input.ApplicationID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	if !reminder.DueAt.Equal(input.DueAt) {
+		reminder.NotifiedAt = nil // Snoozed or moved, notify again when due
+	}
+	reminder.Title = input.Title
+	reminder.DueAt = input.DueAt
+	reminder.ApplicationID = input.ApplicationID
+	if !input.Completed {
+		reminder.CompletedAt = nil
+	} else if reminder.CompletedAt == nil {
+		now := time.Now()
+		reminder.CompletedAt = &now
+	}
+
+	if err := config.DB.Save(&reminder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reminder: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reminder)
+}
+
+func DeleteReminder(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).Delete(&models.Reminder{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete reminder: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reminder deleted successfully"})
+}
+
+// ownsApplication reports whether an optional application reference belongs to the user
+func ownsApplication(userID uint, applicationID *uint) bool {
+	if applicationID == nil {
+		return true
+	}
+
+	var count int64
+	config.DB.Model(&models.Application{}).Where("id = ? AND user_id = ?", *applicationID, userID).Count(&count)
+	return count > 0
+}
@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type tagInput struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color"` // Optional hex color
+}
+
+var tagColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// GetTags lists the user's tags with how many applications carry each one.
+// Applications in the trash aren't counted.
+func GetTags(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var tags []struct {
+		models.Tag
+		ApplicationCount int `json:"application_count"`
+	}
+	config.DB.Model(&models.Tag{}).
+		Select("tags.*, COUNT(applications.id) AS application_count").
+		Joins("LEFT JOIN application_tags ON application_tags.tag_id = tags.id").
+		Joins("LEFT JOIN applications ON applications.id = application_tags.application_id AND applications.deleted_at IS NULL").
+		Where("tags.user_id = ?", user.ID).
+		Group("tags.id").
+		Order("tags.name ASC").
+		Scan(&tags)
+
+	c.JSON(http.StatusOK, tags)
+}
+
+func CreateTag(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input tagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag data: " + err.Error()})
+		return
+	}
+
+	tag := models.Tag{UserID: user.ID}
+	if !applyTagInput(c, &tag, &input) {
+		return
+	}
+
+	if err := config.DB.Create(&tag).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+func UpdateTag(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var tag models.Tag
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	var input tagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag data: " + err.Error()})
+		return
+	}
+	if !applyTagInput(c, &tag, &input) {
+		return
+	}
+
+	if err := config.DB.Save(&tag).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tag: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// applyTagInput validates the input and copies it onto tag, responding with
+// an error and returning false if it is invalid
+func applyTagInput(c *gin.Context, tag *models.Tag, input *tagInput) bool {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tag name is required"})
+		return false
+	}
+	if input.Color != "" && !tagColor.MatchString(input.Color) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Color must be a hex color like #ff8800"})
+		return false
+	}
+
+	var count int64
+	config.DB.Model(&models.Tag{}).Where("user_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", tag.UserID, name, tag.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You already have a tag named " + name})
+		return false
+	}
+
+	tag.Name = name
+	if input.Color != "" {
+		tag.Color = strings.ToLower(input.Color)
+	}
+	return true
+}
+
+// DeleteTag deletes a tag and removes it from every application
+func DeleteTag(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var tag models.Tag
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM application_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&tag).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tag: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+// AttachTags adds tags to an application
+func AttachTags(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var app models.Application
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&app).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	var input struct {
+		TagIDs []uint `json:"tag_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag data: " + err.Error()})
+		return
+	}
+
+	var tags []models.Tag
+	config.DB.Where("id IN ? AND user_id = ?", input.TagIDs, user.ID).Find(&tags)
+	if len(tags) != len(uniqueIDs(input.TagIDs)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&app).Association("Tags").Append(tags); err != nil {
+			return err
+		}
+		return publishTagChange(tx, &app)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to tag application: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, app)
+}
+
+// DetachTag removes a tag from an application
+func DetachTag(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var app models.Application
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&app).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	var tag models.Tag
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("tagId"), user.ID).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&app).Association("Tags").Delete(&tag); err != nil {
+			return err
+		}
+		return publishTagChange(tx, &app)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to untag application: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, app)
+}
+
+// publishTagChange reloads the application's tags and publishes the update
+func publishTagChange(tx *gorm.DB, app *models.Application) error {
+	app.Tags = nil
+	if err := tx.Model(app).Association("Tags").Find(&app.Tags); err != nil {
+		return err
+	}
+	return services.PublishApplicationEvent(tx, models.EventApplicationUpdated, app)
+}
+
+// filterByTags applies the ?tags=1,2&tag_match=any|all filter to an
+// application query. With "all" an application needs every listed tag.
+func filterByTags(c *gin.Context, query *gorm.DB) *gorm.DB {
+	tagIDs := parseIDList(c.Query("tags"))
+	if len(tagIDs) == 0 {
+		return query
+	}
+
+	if c.Query("tag_match") == "all" {
+		return query.Where("id IN (SELECT application_id FROM application_tags WHERE tag_id IN ? GROUP BY application_id HAVING COUNT(DISTINCT tag_id) = ?)",
+			tagIDs, len(tagIDs))
+	}
+	return query.Where("id IN (SELECT application_id FROM application_tags WHERE tag_id IN ?)", tagIDs)
+}
+
+func parseIDList(value string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err == nil && id > 0 {
+			ids = append(ids, uint(id))
+		}
+	}
+	return uniqueIDs(ids)
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ParseTerm shows how ?q= would be normalized, e.g. "S25" as "Summer 2025"
+func ParseTerm(c *gin.Context) {
+	season, year, ok := services.ParseTerm(c.Query("q"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unrecognized term, use a season and year like Summer 2025"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"season": season,
+		"year":   year,
+		"name":   services.TermName(season, year),
+	})
+}
+
+// GetTerms lists the terms of the user's applications with stats for each,
+// newest first. ?archived= works as for the application list.
+func GetTerms(c *gin.Context) {
+	getTermGroups(c, false)
+}
+
+// GetApplicationsByTerm returns the user's applications grouped by term. It
+// takes the same filters as GetApplications.
+func GetApplicationsByTerm(c *gin.Context) {
+	getTermGroups(c, true)
+}
+
+func getTermGroups(c *gin.Context, withApplications bool) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := config.DB
+	if withApplications {
+		query = query.Preload("Tags")
+	}
+	query = applicationListQuery(c, query, user)
+
+	var apps []models.Application
+	if err := query.Order("applied_date DESC, company ASC").Find(&apps).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load applications: " + err.Error()})
+		return
+	}
+
+	groups, err := services.GroupByTerm(config.DB, apps, user.ActiveTermID, withApplications)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to group applications: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+func GetActiveTerm(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var term *models.Term
+	if user.ActiveTermID != nil {
+		term = &models.Term{}
+		if err := config.DB.First(term, *user.ActiveTermID).Error; err != nil {
+			term = nil
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"active_term": term})
+}
+
+// SetActiveTerm sets the term new applications default to. An empty term
+// clears it.
+func SetActiveTerm(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input struct {
+		Term string `json:"term"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term data: " + err.Error()})
+		return
+	}
+
+	var term *models.Term
+	if strings.TrimSpace(input.Term) != "" {
+		season, year, ok := services.ParseTerm(input.Term)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unrecognized term, use a season and year like Summer 2025"})
+			return
+		}
+		if term, err = services.FindOrCreateTerm(config.DB, season, year); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set active term: " + err.Error()})
+			return
+		}
+	}
+
+	var termID *uint
+	if term != nil {
+		termID = &term.ID
+	}
+	if err := config.DB.Model(&models.User{}).Where("id = ?", user.ID).Update("active_term_id", termID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set active term: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"active_term": term})
+}
+
+// linkApplicationTerm normalizes the application's term as part of tx. New
+// applications without a term get the user's active term.
+func linkApplicationTerm(tx *gorm.DB, app *models.Application, user *models.User) error {
+	if app.ID == 0 && strings.TrimSpace(app.Term) == "" && user.ActiveTermID != nil {
+		var term models.Term
+		if err := tx.First(&term, *user.ActiveTermID).Error; err == nil {
+			app.Term = term.Name
+		}
+	}
+	return services.LinkTerm(tx, app)
+}
+
+// filterByTerm applies the ?term= filter to an application query. It takes
+// a term in any form ParseTerm reads, or "active" for the active term.
+func filterByTerm(c *gin.Context, query *gorm.DB, user *models.User) *gorm.DB {
+	value := strings.TrimSpace(c.Query("term"))
+	if value == "" {
+		return query
+	}
+
+	if value == "active" {
+		if user.ActiveTermID == nil {
+			return query.Where("1 = 0")
+		}
+		return query.Where("term_id = ?", *user.ActiveTermID)
+	}
+
+	if season, year, ok := services.ParseTerm(value); ok {
+		return query.Where("term_id IN (SELECT id FROM terms WHERE season = ? AND year = ?)", season, year)
+	}
+	return query.Where("LOWER(term) = LOWER(?)", value)
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+func GetTokenScopes(c *gin.Context) {
+	c.JSON(http.StatusOK, models.TokenScopes)
+}
+
+func GetAccessTokens(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var tokens []models.PersonalAccessToken
+	config.DB.Where("user_id = ?", user.ID).Order("created_at DESC").Find(&tokens)
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreateAccessToken issues a personal access token. The token is only
+// included in this response, afterwards just its prefix can be seen.
+func CreateAccessToken(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input struct {
+		Name      string     `json:"name" binding:"required,max=100"`
+		Scopes    []string   `json:"scopes" binding:"required,min=1"`
+		ExpiresAt *time.Time `json:"expires_at"` // Omit for a token that never expires
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token data: " + err.Error()})
+		return
+	}
+
+	for _, scope := range input.Scopes {
+		if !isTokenScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown scope: " + scope})
+			return
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Expiry must be in the future"})
+		return
+	}
+
+	plaintext, hash := services.GeneratePersonalAccessToken()
+	token := models.PersonalAccessToken{
+		UserID:      user.ID,
+		Name:        input.Name,
+		TokenPrefix: plaintext[:len(services.PersonalAccessTokenPrefix)+8],
+		TokenHash:   hash,
+		Scopes:      input.Scopes,
+		ExpiresAt:   input.ExpiresAt,
+	}
+
+	if err := config.DB.Create(&token).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":        plaintext,
+		"access_token": token,
+	})
+}
+
+func DeleteAccessToken(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).Delete(&models.PersonalAccessToken{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Token not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token revoked successfully"})
+}
+
+func isTokenScope(scope string) bool {
+	for _, s := range models.TokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type webhookInput struct {
+	URL         string   `json:"url" binding:"required"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types" binding:"required,min=1"`
+	Active      *bool    `json:"active"`
+}
+
+// webhookWithSecret is only returned when an endpoint is created or its
+// secret is rotated, the secret can't be read back afterwards
+type webhookWithSecret struct {
+	models.WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+func (input *webhookInput) validate() string {
+	if err := services.ValidateWebhookURL(input.URL); err != nil {
+		return err.Error()
+	}
+	for _, eventType := range input.EventTypes {
+		if !isWebhookEventType(eventType) {
+			return "Unknown event type: " + eventType
+		}
+	}
+	return ""
+}
+
+func isWebhookEventType(eventType string) bool {
+	for _, t := range models.WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// findWebhook loads one of the current user's endpoints, responding 404 if it doesn't exist
+func findWebhook(c *gin.Context, userID uint) (*models.WebhookEndpoint, bool) {
+	var endpoint models.WebhookEndpoint
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&endpoint).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return nil, false
+	}
+	return &endpoint, true
+}
+
+func GetWebhookEventTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, models.WebhookEventTypes)
+}
+
+func GetWebhooks(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var endpoints []models.WebhookEndpoint
+	config.DB.Where("user_id = ?", user.ID).Order("created_at ASC").Find(&endpoints)
+	c.JSON(http.StatusOK, endpoints)
+}
+
+func CreateWebhook(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input webhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook data: " + err.Error()})
+		return
+	}
+	if msg := input.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	endpoint := models.WebhookEndpoint{
+		UserID:      user.ID,
+		URL:         input.URL,
+		Description: input.Description,
+		Secret:      services.GenerateWebhookSecret(),
+		EventTypes:  input.EventTypes,
+		Active:      true,
+	}
+
+	if err := config.DB.Create(&endpoint).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhookWithSecret{endpoint, endpoint.Secret})
+}
+
+func UpdateWebhook(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	endpoint, ok := findWebhook(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var input webhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook data: " + err.Error()})
+		return
+	}
+	if msg := input.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	endpoint.URL = input.URL
+	endpoint.Description = input.Description
+	endpoint.EventTypes = input.EventTypes
+	if input.Active != nil {
+		endpoint.Active = *input.Active
+	}
+
+	if err := config.DB.Save(endpoint).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+func DeleteWebhook(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	endpoint, ok := findWebhook(c, user.ID)
+	if !ok {
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint_id = ?", endpoint.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(endpoint).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func RotateWebhookSecret(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	endpoint, ok := findWebhook(c, user.ID)
+	if !ok {
+		return
+	}
+
+	endpoint.Secret = services.GenerateWebhookSecret()
+	if err := config.DB.Save(endpoint).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate secret: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhookWithSecret{*endpoint, endpoint.Secret})
+}
+
+// TestWebhook sends a webhook.test event immediately and returns the delivery
+// result, so users can check their receiver and signature verification
+func TestWebhook(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	endpoint, ok := findWebhook(c, user.ID)
+	if !ok {
+		return
+	}
+
+	delivery, err := services.SendTestWebhook(endpoint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send test event: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// GetWebhookDeliveries returns the endpoint's delivery log, newest first
+func GetWebhookDeliveries(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	endpoint, ok := findWebhook(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var deliveries []models.WebhookDelivery
+	config.DB.Where("endpoint_id = ?", endpoint.ID).Order("created_at DESC").Limit(100).Find(&deliveries)
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RedeliverWebhook queues a past delivery to be sent again with a fresh retry budget
+func RedeliverWebhook(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	endpoint, ok := findWebhook(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var delivery models.WebhookDelivery
+	if err := config.DB.Where("id = ? AND endpoint_id = ?", c.Param("deliveryId"), endpoint.ID).First(&delivery).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+
+	delivery.Status = models.WebhookPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+
+	if err := config.DB.Save(&delivery).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redeliver: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
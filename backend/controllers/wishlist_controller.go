@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type wishlistInput struct {
+	Company     string     `json:"company" binding:"required,max=100"`
+	Position    string     `json:"position" binding:"required"`
+	Location    string     `json:"location"`
+	Term        string     `json:"term"`
+	PostingURL  string     `json:"posting_url"`
+	Description string     `json:"description"`
+	Deadline    *time.Time `json:"deadline"`
+	DatePosted  *time.Time `json:"date_posted"`
+	Priority    string     `json:"priority"` // Defaults to medium
+	Note        string     `json:"note" binding:"max=1048"`
+}
+
+// wishlistOrder puts the nearest deadline first, then the highest priority
+const wishlistOrder = "deadline IS NULL, deadline ASC, " +
+	"CASE priority WHEN 'high' THEN 0 WHEN 'medium' THEN 1 ELSE 2 END, created_at ASC"
+
+// findWishlistItem loads one of the current user's wishlist items, responding 404 if it doesn't exist
+func findWishlistItem(c *gin.Context, userID uint) (*models.WishlistItem, bool) {
+	var item models.WishlistItem
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&item).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wishlist item not found"})
+		return nil, false
+	}
+	return &item, true
+}
+
+// GetWishlist lists the postings the user intends to apply to, nearest
+// deadline first. ?priority=high only lists one priority and ?upcoming=true
+// leaves out items whose deadline has passed.
+func GetWishlist(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := config.DB.Where("user_id = ?", user.ID)
+	if priority := c.Query("priority"); priority != "" {
+		query = query.Where("priority = ?", priority)
+	}
+	if c.Query("upcoming") == "true" {
+		query = query.Where("deadline IS NULL OR deadline >= ?", time.Now())
+	}
+
+	var items []models.WishlistItem
+	query.Order(wishlistOrder).Find(&items)
+	c.JSON(http.StatusOK, items)
+}
+
+func GetWishlistItem(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	item, ok := findWishlistItem(c, user.ID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func CreateWishlistItem(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input wishlistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist data: " + err.Error()})
+		return
+	}
+
+	item := models.WishlistItem{UserID: user.ID}
+	if !applyWishlistInput(c, &item, &input) {
+		return
+	}
+
+	if err := config.DB.Create(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create wishlist item: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func UpdateWishlistItem(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	item, ok := findWishlistItem(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var input wishlistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist data: " + err.Error()})
+		return
+	}
+	if !applyWishlistInput(c, item, &input) {
+		return
+	}
+
+	if err := config.DB.Save(item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update wishlist item: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func DeleteWishlistItem(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).Delete(&models.WishlistItem{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete wishlist item: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wishlist item not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Wishlist item deleted successfully"})
+}
+
+// ConvertWishlistItem promotes a wishlist item into an application with
+// status Applied and today's date in the user's time zone. The item is
+// removed from the wishlist; the resume can be added by updating the
+// application.
+func ConvertWishlistItem(c *gin.Context) {
+	user, err := getCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	item, ok := findWishlistItem(c, user.ID)
+	if !ok {
+		return
+	}
+
+	today := time.Now().In(services.UserLocation(user))
+	app := models.Application{
+		Company:     item.Company,
+		Position:    item.Position,
+		Status:      models.StatusApplied,
+		Location:    item.Location,
+		AppliedDate: time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC),
+		Term:        item.Term,
+		Note:        item.Note,
+		PostingURL:  item.PostingURL,
+		Description: item.Description,
+		Deadline:    item.Deadline,
+		UserID:      user.ID,
+	}
+	services.ApplyLocation(&app)
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := linkApplicationCompany(tx, &app, nil); err != nil {
+			return err
+		}
+		if err := linkApplicationTerm(tx, &app, user); err != nil {
+			return err
+		}
+		if err := tx.Create(&app).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(item).Error; err != nil {
+			return err
+		}
+		return services.PublishApplicationEvent(tx, models.EventApplicationCreated, &app)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to convert wishlist item: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, app)
+}
+
+// applyWishlistInput validates input and copies it onto item, responding
+// with an error and returning false if it is invalid
+func applyWishlistInput(c *gin.Context, item *models.WishlistItem, input *wishlistInput) bool {
+	company, position := strings.TrimSpace(input.Company), strings.TrimSpace(input.Position)
+	if company == "" || position == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Company and position are required"})
+		return false
+	}
+
+	postingURL := strings.TrimSpace(input.PostingURL)
+	if postingURL != "" && !services.IsHTTPURL(postingURL) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Posting URL must be an http or https URL"})
+		return false
+	}
+
+	priority, ok := wishlistPriority(input.Priority)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Priority must be low, medium or high"})
+		return false
+	}
+
+	item.Company = company
+	item.Position = position
+	item.Location = strings.TrimSpace(input.Location)
+	item.Term = wishlistTerm(input.Term)
+	item.PostingURL = postingURL
+	item.Description = strings.TrimSpace(input.Description)
+	item.Deadline = input.Deadline
+	item.DatePosted = input.DatePosted
+	item.Priority = priority
+	item.Note = input.Note
+	return true
+}
+
+// wishlistPriority validates a priority, empty meaning medium
+func wishlistPriority(value string) (string, bool) {
+	switch value {
+	case "":
+		return models.PriorityMedium, true
+	case models.PriorityLow, models.PriorityMedium, models.PriorityHigh:
+		return value, true
+	}
+	return "", false
+}
+
+// wishlistTerm normalizes a term like "S25" to "Summer 2025" when it can be
+// parsed. Wishlist items don't link terms, that happens on conversion.
+func wishlistTerm(value string) string {
+	value = strings.TrimSpace(value)
+	if season, year, ok := services.ParseTerm(value); ok {
+		return services.TermName(season, year)
+	}
+	return value
+}
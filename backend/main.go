@@ -9,6 +9,8 @@ import (
 	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/controllers"
 
 	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/middleware"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -32,6 +34,26 @@ func main() {
 	config.PrintConfig()
 
 	config.ConnectDB()
+	services.BackfillCompanies()
+	services.BackfillLocations()
+	services.BackfillTerms()
+
+	if err := services.LoadSigningKeys(); err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+	services.StartSigningKeyReloader()
+
+	// Background workers
+	services.StartOutboxWorker()
+	services.StartDigestWorker()
+	services.StartNotificationWorker()
+	services.StartEventListener()
+	services.StartWebhookWorker()
+	services.StartAccountDeletionWorker()
+	services.StartExportWorker()
+	services.StartTrashPurgeWorker()
+	services.StartEmailIngestionWorker()
+	services.StartAutomationWorker()
 
 	r := gin.Default()
 
@@ -50,29 +72,208 @@ func main() {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Last-Event-ID"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 	}))
 
+	r.GET("/.well-known/jwks.json", controllers.GetJWKS)
 	r.POST("/auth/signup", controllers.CreateUser)
 	r.POST("/auth/login", controllers.Login)
 	r.GET("/auth/verify-email", controllers.VerifyEmail)
 	r.POST("/auth/resend-verification", controllers.ResendVerification)
+	r.GET("/auth/oidc/providers", controllers.GetOIDCProviders)
+	r.GET("/auth/oidc/:provider/login", controllers.OIDCLogin)
+	r.GET("/auth/oidc/:provider/callback", controllers.OIDCCallback)
+	r.GET("/digest/unsubscribe", controllers.UnsubscribeDigest)
+	r.POST("/digest/unsubscribe", controllers.UnsubscribeDigest)
+	r.GET("/exports/download", controllers.DownloadExportLink)
 
+	// Live application updates, EventSource can't send an Authorization header
+	// so it passes a short-lived ticket instead
+	r.GET("/events/stream", middleware.CheckStreamTicket,
+		middleware.RequireScope(models.ScopeReadApplications), controllers.StreamEvents)
+	r.POST("/events/ticket", middleware.CheckAuth,
+		middleware.RequireScope(models.ScopeReadApplications), controllers.CreateStreamTicket)
+
+	// Protected routes accept browser sessions and personal access tokens.
+	// Each group below checks the token's scopes: read scopes for GET
+	// requests, write scopes for everything else.
 	protected := r.Group("/")
 	protected.Use(middleware.CheckAuth)
+
+	profile := protected.Group("/user", middleware.RequireScopes(models.ScopeReadProfile, models.ScopeWriteProfile))
 	{
 		// User profile
-		protected.GET("/user/profile", controllers.GetUserProfile)
+		profile.GET("/profile", controllers.GetUserProfile)
+		profile.GET("/digest", controllers.GetDigestSettings)
+		profile.PUT("/digest", controllers.UpdateDigestSettings)
+		profile.GET("/digest/preview", controllers.PreviewDigest)
+		profile.GET("/notification-settings", controllers.GetNotificationSettings)
+		profile.PUT("/notification-settings", controllers.UpdateNotificationSettings)
+		profile.GET("/active-term", controllers.GetActiveTerm)
+		profile.PUT("/active-term", controllers.SetActiveTerm)
+		profile.GET("/email-ingestion", controllers.GetEmailIngestionSettings)
+		profile.PUT("/email-ingestion", controllers.UpdateEmailIngestionSettings)
+	}
+
+	// Account settings can only be changed from a browser session
+	account := protected.Group("/user/account", middleware.RequireSession)
+	{
+		account.PUT("/password", controllers.ChangePassword)
+		account.PUT("/email", controllers.RequestEmailChange)
+		account.PUT("/username", controllers.ChangeUsername)
+		account.DELETE("", controllers.DeleteAccount)
+		account.POST("/restore", controllers.RestoreAccount)
+	}
+
+	// Data exports contain everything, so they need a browser session too
+	exports := protected.Group("/user/exports", middleware.RequireSession)
+	{
+		exports.GET("", controllers.GetExports)
+		exports.POST("", controllers.CreateExport)
+		exports.GET("/:id/download", controllers.DownloadExport)
+		exports.POST("/restore", controllers.RestoreExport)
+	}
+
+	// Personal access tokens can only be managed from a browser session
+	tokens := protected.Group("/user/tokens", middleware.RequireSession)
+	{
+		tokens.GET("", controllers.GetAccessTokens)
+		tokens.GET("/scopes", controllers.GetTokenScopes)
+		tokens.POST("", controllers.CreateAccessToken)
+		tokens.DELETE("/:id", controllers.DeleteAccessToken)
+	}
 
+	notifications := protected.Group("/notifications", middleware.RequireScopes(models.ScopeReadNotifications, models.ScopeWriteNotifications))
+	{
+		// In-app notifications
+		notifications.GET("", controllers.GetNotifications)
+		notifications.PATCH("/:id/read", controllers.MarkNotificationRead)
+		notifications.POST("/read-all", controllers.MarkAllNotificationsRead)
+	}
+
+	applications := protected.Group("/", middleware.RequireScopes(models.ScopeReadApplications, models.ScopeWriteApplications))
+	{
 		// Application routes - all protected and user-specific
-		protected.GET("/applications", controllers.GetApplications)
-		protected.GET("/applications/:id", controllers.GetApplicationByID)
-		protected.POST("/applications", controllers.CreateApplication)
-		protected.PUT("/applications/:id", controllers.UpdateApplication)
-		protected.PATCH("/applications/:id/status", controllers.UpdateApplicationStatus)
-		protected.DELETE("/applications/:id", controllers.DeleteApplication)
+		applications.GET("/applications", controllers.GetApplications)
+		applications.GET("/applications/facets", controllers.GetApplicationFacets)
+		applications.POST("/applications/capture", controllers.CapturePosting)
+		applications.GET("/applications/:id", controllers.GetApplicationByID)
+		applications.POST("/applications", controllers.CreateApplication)
+		applications.PUT("/applications/:id", controllers.UpdateApplication)
+		applications.PATCH("/applications/:id/status", controllers.UpdateApplicationStatus)
+		applications.DELETE("/applications/:id", controllers.DeleteApplication)
+
+		// Deleted applications stay in the trash until TRASH_RETENTION has passed
+		applications.GET("/applications/trash", controllers.GetTrash)
+		applications.POST("/applications/:id/restore", controllers.RestoreApplication)
+		applications.DELETE("/applications/trash/:id", controllers.PurgeApplication)
+
+		// Finished seasons
+		applications.POST("/applications/archive", controllers.ArchiveTerm)
+		applications.POST("/applications/unarchive", controllers.UnarchiveTerm)
+		applications.POST("/applications/bulk", controllers.BulkUpdateApplications)
+
+		// Tags
+		applications.GET("/tags", controllers.GetTags)
+		applications.POST("/tags", controllers.CreateTag)
+		applications.PUT("/tags/:id", controllers.UpdateTag)
+		applications.DELETE("/tags/:id", controllers.DeleteTag)
+		applications.POST("/applications/:id/tags", controllers.AttachTags)
+		applications.DELETE("/applications/:id/tags/:tagId", controllers.DetachTag)
+
+		// Terms
+		applications.GET("/terms", controllers.GetTerms)
+		applications.GET("/terms/parse", controllers.ParseTerm)
+		applications.GET("/terms/applications", controllers.GetApplicationsByTerm)
+
+		// Offers
+		applications.GET("/offers", controllers.GetOffers)
+		applications.GET("/offers/compare", controllers.CompareOffers)
+		applications.GET("/applications/:id/offer", controllers.GetOffer)
+		applications.PUT("/applications/:id/offer", controllers.SaveOffer)
+		applications.DELETE("/applications/:id/offer", controllers.DeleteOffer)
+
+		// Imports from other trackers
+		applications.GET("/imports/formats", controllers.GetImportFormats)
+		applications.POST("/imports/preview", controllers.PreviewImport)
+		applications.POST("/imports", controllers.ImportApplications)
+
+		// Wishlist of postings to apply to
+		applications.GET("/wishlist", controllers.GetWishlist)
+		applications.GET("/wishlist/:id", controllers.GetWishlistItem)
+		applications.POST("/wishlist", controllers.CreateWishlistItem)
+		applications.POST("/wishlist/import", controllers.ImportListings)
+		applications.PUT("/wishlist/:id", controllers.UpdateWishlistItem)
+		applications.DELETE("/wishlist/:id", controllers.DeleteWishlistItem)
+		applications.POST("/wishlist/:id/convert", controllers.ConvertWishlistItem)
+
+		// Emails from employers, matched to applications
+		applications.GET("/emails", controllers.GetInboundEmails)
+		applications.POST("/emails", controllers.UploadEmail)
+		applications.POST("/emails/preview", controllers.PreviewEmail)
+		applications.POST("/emails/:id/apply", controllers.ApplyInboundEmail)
+		applications.POST("/emails/:id/dismiss", controllers.DismissInboundEmail)
+		applications.GET("/email-rules", controllers.GetEmailRules)
+		applications.POST("/email-rules", controllers.CreateEmailRule)
+		applications.PUT("/email-rules/:id", controllers.UpdateEmailRule)
+		applications.DELETE("/email-rules/:id", controllers.DeleteEmailRule)
+
+		// Automation rules
+		applications.GET("/automation-rules", controllers.GetAutomationRules)
+		applications.POST("/automation-rules", controllers.CreateAutomationRule)
+		applications.POST("/automation-rules/preview", controllers.PreviewAutomationRule)
+		applications.GET("/automation-rules/executions", controllers.GetAutomationExecutions)
+		applications.PUT("/automation-rules/:id", controllers.UpdateAutomationRule)
+		applications.DELETE("/automation-rules/:id", controllers.DeleteAutomationRule)
+		applications.POST("/automation-rules/:id/preview", controllers.PreviewSavedAutomationRule)
+
+		// Companies
+		applications.GET("/companies", controllers.GetCompanies)
+		applications.GET("/companies/autocomplete", controllers.AutocompleteCompanies)
+		applications.GET("/companies/suggestions", controllers.SuggestCompanies)
+		applications.GET("/companies/:id", controllers.GetCompany)
+		applications.POST("/companies", controllers.CreateCompany)
+		applications.PUT("/companies/:id", controllers.UpdateCompany)
+		applications.DELETE("/companies/:id", controllers.DeleteCompany)
+		applications.POST("/companies/:id/merge", controllers.MergeCompanies)
+
+		// Interviews and reminders
+		applications.GET("/interviews", controllers.GetInterviews)
+		applications.POST("/applications/:id/interviews", controllers.CreateInterview)
+		applications.PUT("/interviews/:id", controllers.UpdateInterview)
+		applications.DELETE("/interviews/:id", controllers.DeleteInterview)
+		applications.GET("/reminders", controllers.GetReminders)
+		applications.POST("/reminders", controllers.CreateReminder)
+		applications.PUT("/reminders/:id", controllers.UpdateReminder)
+		applications.DELETE("/reminders/:id", controllers.DeleteReminder)
+	}
+
+	analytics := protected.Group("/analytics", middleware.RequireScope(models.ScopeReadAnalytics))
+	{
+		analytics.GET("/seasons", controllers.GetSeasonAnalytics)
+		analytics.GET("/locations", controllers.GetLocationAnalytics)
+	}
+
+	webhooks := protected.Group("/webhooks", middleware.RequireScopes(models.ScopeReadWebhooks, models.ScopeWriteWebhooks))
+	{
+		// Outgoing webhooks
+		webhooks.GET("", controllers.GetWebhooks)
+		webhooks.GET("/event-types", controllers.GetWebhookEventTypes)
+		webhooks.POST("", controllers.CreateWebhook)
+		webhooks.PUT("/:id", controllers.UpdateWebhook)
+		webhooks.DELETE("/:id", controllers.DeleteWebhook)
+		webhooks.POST("/:id/rotate-secret", controllers.RotateWebhookSecret)
+		webhooks.POST("/:id/test", controllers.TestWebhook)
+		webhooks.GET("/:id/deliveries", controllers.GetWebhookDeliveries)
+		webhooks.POST("/:id/deliveries/:deliveryId/redeliver", controllers.RedeliverWebhook)
+	}
+
+	admin := protected.Group("/admin", middleware.RequireSession, middleware.RequireAdmin)
+	{
+		admin.GET("/outbox", controllers.GetOutboxEmails)
+		admin.POST("/outbox/:id/retry", controllers.RetryOutboxEmail)
 	}
 
 	r.Static("/uploads", "./uploads")
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin must run after CheckAuth and only lets admin users through
+func RequireAdmin(c *gin.Context) {
+	user, ok := c.MustGet("currentUser").(models.User)
+	if !ok || !user.IsAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
@@ -1,15 +1,14 @@
 package middleware
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
 	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 )
 
 func CheckAuth(c *gin.Context) {
@@ -29,41 +28,67 @@ func CheckAuth(c *gin.Context) {
 	}
 
 	tokenString := authToken[1]
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(config.GetJWTSecret()), nil
-	})
-
-	if err != nil || !token.Valid {
+	if strings.HasPrefix(tokenString, services.PersonalAccessTokenPrefix) {
+		checkPersonalAccessToken(c, tokenString)
+		return
+	}
+
+	claims, err := services.ParseAuthToken(tokenString)
+	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-		c.Abort()
+	var user models.User
+	config.DB.Where("ID=?", claims.UserID).Find(&user)
+
+	if user.ID == 0 {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	// Sessions are revoked by bumping the user's session version
+	if claims.SessionVersion != user.SessionVersion {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been signed out"})
+		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
-	if float64(time.Now().Unix()) > claims["exp"].(float64) {
+	c.Set("currentUser", user)
+
+	c.Next()
+}
+func checkPersonalAccessToken(c *gin.Context, tokenString string) {
+	var token models.PersonalAccessToken
+	if err := config.DB.Where("token_hash = ?", services.HashAccessToken(tokenString)).First(&token).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "token expired"})
 		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
 	var user models.User
-	config.DB.Where("ID=?", claims["id"]).Find(&user)
+	config.DB.Where("ID=?", token.UserID).Find(&user)
 
 	if user.ID == 0 {
 		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
+	// Only record usage once a minute so busy scripts don't write on every request
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > time.Minute {
+		config.DB.Model(&token).UpdateColumn("last_used_at", now)
+	}
+
 	c.Set("currentUser", user)
+	c.Set("accessToken", &token)
 
 	c.Next()
-}
\ No newline at end of file
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope only lets personal access tokens through if they were granted
+// scope. Browser sessions are not restricted. It must run after CheckAuth.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token, ok := c.Get("accessToken"); ok && !token.(*models.PersonalAccessToken).HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Token is missing the " + scope + " scope"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScopes is RequireScope with readScope for GET requests and
+// writeScope for everything else, for route groups mixing both
+func RequireScopes(readScope, writeScope string) gin.HandlerFunc {
+	read, write := RequireScope(readScope), RequireScope(writeScope)
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			read(c)
+		} else {
+			write(c)
+		}
+	}
+}
+
+// RequireSession rejects personal access tokens, for routes like token
+// management that should only be used from a logged in browser session
+func RequireSession(c *gin.Context) {
+	if _, ok := c.Get("accessToken"); ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This endpoint can't be used with a personal access token"})
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
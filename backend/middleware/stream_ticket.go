@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CheckStreamTicket authenticates clients that can't set headers, like the
+// browser EventSource API, with a ?ticket= from POST /events/ticket. Tokens
+// are never accepted in the URL, where they would end up in logs. Requests
+// with an Authorization header go through CheckAuth instead.
+func CheckStreamTicket(c *gin.Context) {
+	if c.GetHeader("Authorization") != "" {
+		CheckAuth(c)
+		return
+	}
+
+	user, err := services.CheckStreamTicket(config.DB, c.Query("ticket"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired stream ticket"})
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	c.Set("currentUser", *user)
+
+	c.Next()
+}
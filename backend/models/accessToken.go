@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Scopes a personal access token can be granted. Browser sessions (JWTs)
+// implicitly have all of them.
+const (
+	ScopeReadApplications   = "read:applications" // Applications, interviews, reminders and live events
+	ScopeWriteApplications  = "write:applications"
+	ScopeReadAnalytics      = "read:analytics"
+	ScopeReadNotifications  = "read:notifications"
+	ScopeWriteNotifications = "write:notifications"
+	ScopeReadWebhooks       = "read:webhooks"
+	ScopeWriteWebhooks      = "write:webhooks"
+	ScopeReadProfile        = "read:profile" // Profile and settings
+	ScopeWriteProfile       = "write:profile"
+)
+
+var TokenScopes = []string{
+	ScopeReadApplications,
+	ScopeWriteApplications,
+	ScopeReadAnalytics,
+	ScopeReadNotifications,
+	ScopeWriteNotifications,
+	ScopeReadWebhooks,
+	ScopeWriteWebhooks,
+	ScopeReadProfile,
+	ScopeWriteProfile,
+}
+
+// PersonalAccessToken is a long-lived API token for scripts and integrations.
+// Only a hash of the token is stored, the token itself is shown once.
+type PersonalAccessToken struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"index;not null" json:"user_id"`
+	Name        string     `gorm:"not null" json:"name"`
+	TokenPrefix string     `gorm:"not null" json:"token_prefix"` // First characters, to tell tokens apart
+	TokenHash   string     `gorm:"uniqueIndex;not null" json:"-"`
+	Scopes      []string   `gorm:"type:text;serializer:json" json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at"` // Never expires if nil
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// HasScope reports whether the token was granted scope
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type ApplicationStatus uint8
 
@@ -29,16 +33,61 @@ func (s ApplicationStatus) String() string {
 	}
 }
 
+// WorkMode is where the work happens
+type WorkMode string
+
+const (
+	WorkModeRemote WorkMode = "remote"
+	WorkModeHybrid WorkMode = "hybrid"
+	WorkModeOnsite WorkMode = "onsite"
+)
+
 type Application struct {
 	ID          uint              `gorm:"primaryKey" json:"id"`
 	Company     string            `json:"company"`
+	CompanyID   *uint             `gorm:"index" json:"company_id"` // Set from Company when the application is saved
 	Position    string            `json:"position"`
-	Status      ApplicationStatus `json:"status"` // e.g., "Applied", "Interview", etc.
-	Location    string            `json:"location"`
+	Status      ApplicationStatus `json:"status"`                         // e.g., "Applied", "Interview", etc.
+	Location    string            `json:"location"`                       // As entered, e.g. "NYC (Hybrid)"
+	City        string            `gorm:"size:100" json:"city"`           // Parsed from Location unless set explicitly
+	Region      string            `gorm:"size:100" json:"region"`         // State or province, e.g. "NY"
+	Country     string            `gorm:"size:2;index" json:"country"`    // ISO 3166-1 alpha-2, e.g. "US"
+	WorkMode    WorkMode          `gorm:"size:10;index" json:"work_mode"` // remote, hybrid or onsite
 	AppliedDate time.Time         `json:"applied_date"`
 	Term        string            `json:"term"`                            // e.g., "Summer 2025"
+	TermID      *uint             `gorm:"index" json:"term_id"`            // Set when Term can be parsed, Term then holds its normalized name
 	Note        string            `gorm:"size:1048" json:"note,omitempty"` // Optional note, up to 1048 chars
 	ResumeURL   string            `json:"resume_url"`
+	PostingURL  string            `json:"posting_url,omitempty"`                        // Link to the job posting
+	Description string            `gorm:"type:text" json:"description,omitempty"`       // Posting description as plain text
+	Deadline    *time.Time        `json:"deadline,omitempty"`                           // Last day to apply, when the posting gives one
+	Archived    bool              `gorm:"index;not null;default:false" json:"archived"` // Hidden from the default list once a season is over
+	ArchivedAt  *time.Time        `json:"archived_at,omitempty"`
 	UserID      uint              `json:"user_id"`                                 // Set automatically by server
 	User        User              `gorm:"foreignKey:UserID" json:"user,omitempty"` // Only in responses
+	Tags        []Tag             `gorm:"many2many:application_tags;" json:"tags,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `gorm:"index" json:"deleted_at,omitempty"` // Set while the application is in the trash
+
+	// Similar existing companies, only set when saving the application
+	// created a new company that may be a duplicate
+	CompanySuggestions []CompanySuggestion `gorm:"-" json:"company_suggestions,omitempty"`
+}
+
+// WithoutTrashedApplications is a query scope that hides interviews and
+// reminders of applications in the trash
+func WithoutTrashedApplications(db *gorm.DB) *gorm.DB {
+	return db.Where("application_id IS NULL OR application_id NOT IN (SELECT id FROM applications WHERE deleted_at IS NOT NULL)")
+}
+
+// ApplicationStatusChange is one entry in an application's status history
+type ApplicationStatusChange struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	ApplicationID uint              `gorm:"index;not null" json:"application_id"`
+	UserID        uint              `gorm:"index;not null" json:"user_id"`
+	FromStatus    ApplicationStatus `json:"from_status"`
+	ToStatus      ApplicationStatus `json:"to_status"`
+	Automated     bool              `gorm:"not null;default:false" json:"automated"` // Made by an automation rule, which doesn't trigger other rules
+	CreatedAt     time.Time         `gorm:"index" json:"created_at"`
 }
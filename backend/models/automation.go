@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// What makes an automation rule act on an application
+const (
+	TriggerStatusChanged = "status_changed" // The application's status changed, to TriggerStatus if set
+	TriggerCreated       = "created"        // The application was created
+	TriggerSchedule      = "schedule"       // Checked on every run, acts once per status the application is in
+)
+
+// Operators of automation conditions
+const (
+	ConditionEq       = "eq"
+	ConditionNeq      = "neq"
+	ConditionContains = "contains"
+	ConditionGt       = "gt"
+	ConditionGte      = "gte"
+	ConditionLt       = "lt"
+	ConditionLte      = "lte"
+)
+
+// AutomationCondition compares one field of an application to Value, e.g.
+// {"field": "days_in_status", "op": "gte", "value": "21"}
+type AutomationCondition struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+// Automation actions
+const (
+	ActionAddTag         = "add_tag"
+	ActionRemoveTag      = "remove_tag"
+	ActionSetStatus      = "set_status"
+	ActionArchive        = "archive"
+	ActionCreateReminder = "create_reminder"
+)
+
+// AutomationAction is something a rule does to the application it matched
+type AutomationAction struct {
+	Type   string             `json:"type"`
+	Tag    string             `json:"tag,omitempty"`    // add_tag and remove_tag, by name
+	Status *ApplicationStatus `json:"status,omitempty"` // set_status
+	Title  string             `json:"title,omitempty"`  // create_reminder, {company} and {position} are filled in
+	Days   int                `json:"days,omitempty"`   // create_reminder, the reminder is due this many days later
+}
+
+// AutomationRule runs its actions on applications its trigger fires for,
+// as long as they meet all of its conditions
+type AutomationRule struct {
+	ID            uint                  `gorm:"primaryKey" json:"id"`
+	UserID        uint                  `gorm:"index;not null" json:"user_id"`
+	Name          string                `gorm:"size:100;not null" json:"name"`
+	Enabled       bool                  `gorm:"index;not null" json:"enabled"`
+	Trigger       string                `gorm:"size:20;not null" json:"trigger"`      // status_changed, created or schedule
+	TriggerStatus *ApplicationStatus    `json:"trigger_status"`                       // For status_changed, the status it changed to
+	DelayDays     int                   `gorm:"not null;default:0" json:"delay_days"` // For events, how long after them the rule acts
+	Conditions    []AutomationCondition `gorm:"type:text;serializer:json" json:"conditions"`
+	Actions       []AutomationAction    `gorm:"type:text;serializer:json" json:"actions"`
+	// CheckedUntil is how far events have been evaluated. It starts when the
+	// rule is enabled, so earlier events don't trigger it.
+	CheckedUntil time.Time  `json:"-"`
+	LastRunAt    *time.Time `json:"last_run_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Results of automation executions
+const (
+	ExecutionSucceeded = "succeeded"
+	ExecutionFailed    = "failed"
+)
+
+// AutomationExecution logs a rule acting on an application. A rule acts at
+// most once for each trigger key, e.g. "status_change:12".
+type AutomationExecution struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"index;not null" json:"user_id"`
+	RuleID        uint      `gorm:"uniqueIndex:idx_automation_execution;not null" json:"rule_id"`
+	ApplicationID uint      `gorm:"uniqueIndex:idx_automation_execution;index;not null" json:"application_id"`
+	TriggerKey    string    `gorm:"uniqueIndex:idx_automation_execution;size:50;not null" json:"trigger_key"`
+	Result        string    `gorm:"size:10;not null" json:"result"`           // succeeded or failed
+	Actions       []string  `gorm:"type:text;serializer:json" json:"actions"` // What was done
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
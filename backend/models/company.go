@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Company is a user's canonical record for an employer. Applications point
+// at it so "Google", "google" and "Google LLC" count as one company.
+type Company struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	UserID         uint      `gorm:"uniqueIndex:idx_company_user_name;not null" json:"user_id"`
+	Name           string    `gorm:"size:100;not null" json:"name"`
+	NormalizedName string    `gorm:"uniqueIndex:idx_company_user_name;size:100;not null" json:"-"` // Lowercase, without punctuation and legal suffixes
+	Aliases        []string  `gorm:"type:text;serializer:json" json:"aliases"`                     // Other names that resolve to this company
+	Website        string    `json:"website,omitempty"`
+	Industry       string    `gorm:"size:100" json:"industry,omitempty"`
+	Headquarters   string    `gorm:"size:100" json:"headquarters,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CompanySuggestion is an existing company whose name is close to one being
+// entered. Score goes from 0 to 1, where 1 is an exact match.
+type CompanySuggestion struct {
+	Company
+	Score       float64 `json:"score"`
+	MatchedName string  `json:"matched_name"` // The name or alias that matched
+}
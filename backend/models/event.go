@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	EventApplicationCreated = "application.created"
+	EventApplicationUpdated = "application.updated"
+	EventApplicationDeleted = "application.deleted"
+)
+
+// ApplicationEvent is a change to one of a user's applications, kept for a
+// while so clients of the event stream can resume after reconnecting
+type ApplicationEvent struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"index;not null" json:"user_id"`
+	Type          string    `gorm:"not null" json:"type"`
+	ApplicationID uint      `json:"application_id"`
+	Payload       string    `gorm:"type:text" json:"payload"` // JSON encoded application
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type DataExportStatus string
+
+const (
+	ExportPending DataExportStatus = "pending"
+	ExportReady   DataExportStatus = "ready"
+	ExportFailed  DataExportStatus = "failed"
+)
+
+// DataExport is a ZIP archive of a user's data, built in the background for
+// accounts too large to export within a request
+type DataExport struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	UserID      uint             `gorm:"index;not null" json:"user_id"`
+	Status      DataExportStatus `gorm:"index;not null;default:'pending'" json:"status"`
+	FilePath    string           `json:"-"`
+	Size        int64            `json:"size"` // Bytes, once ready
+	Error       string           `gorm:"type:text" json:"error,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"` // The archive is deleted after this
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// UserIdentity links a user to an account at an external identity provider
+type UserIdentity struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"index;not null" json:"user_id"`
+	Provider    string    `gorm:"uniqueIndex:idx_identity_provider_subject;not null" json:"provider"`
+	Subject     string    `gorm:"uniqueIndex:idx_identity_provider_subject;not null" json:"-"` // The provider's "sub" claim
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// OIDCLoginState holds the state, nonce and PKCE verifier of an SSO login
+// between the redirect to the provider and its callback
+type OIDCLoginState struct {
+	ID           uint      `gorm:"primaryKey"`
+	State        string    `gorm:"uniqueIndex;not null"`
+	Provider     string    `gorm:"not null"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"index"`
+	CreatedAt    time.Time
+}
@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// What an inbound email is about
+const (
+	EmailRejection = "rejection"
+	EmailOA        = "oa"
+	EmailInterview = "interview"
+	EmailOffer     = "offer"
+	EmailOther     = "other"
+)
+
+// EmailCategories lists the categories rules can classify emails as
+var EmailCategories = []string{EmailRejection, EmailOA, EmailInterview, EmailOffer}
+
+// Where an inbound email stands
+const (
+	InboundProposed  = "proposed"  // Waiting for the user to apply or dismiss the status change
+	InboundApplied   = "applied"   // The status change was made
+	InboundDismissed = "dismissed" // The user rejected the status change
+	InboundUnmatched = "unmatched" // Classified, but no application was found
+	InboundIgnored   = "ignored"   // Not classified, or no status change needed
+)
+
+// Where an inbound email came from
+const (
+	InboundSourceUpload = "upload"
+	InboundSourceIMAP   = "imap"
+)
+
+// InboundEmail is a received email matched to an application, with the
+// status change it suggests
+type InboundEmail struct {
+	ID             uint               `gorm:"primaryKey" json:"id"`
+	UserID         uint               `gorm:"uniqueIndex:idx_inbound_user_message;not null" json:"user_id"`
+	MessageID      string             `gorm:"uniqueIndex:idx_inbound_user_message;size:255;not null" json:"message_id"`
+	Source         string             `gorm:"size:10;not null" json:"source"` // upload or imap
+	FromAddress    string             `json:"from_address"`
+	FromName       string             `json:"from_name,omitempty"`
+	Subject        string             `json:"subject"`
+	Snippet        string             `gorm:"size:500" json:"snippet"`
+	ReceivedAt     time.Time          `gorm:"index" json:"received_at"`
+	Category       string             `gorm:"size:20;not null" json:"category"`
+	MatchedRule    string             `json:"matched_rule,omitempty"` // Name of the rule that classified it
+	ApplicationID  *uint              `gorm:"index" json:"application_id"`
+	MatchReason    string             `json:"match_reason,omitempty"` // Why it was matched to the application
+	ProposedStatus *ApplicationStatus `json:"proposed_status"`
+	State          string             `gorm:"size:20;index;not null" json:"state"`
+	Application    *Application       `gorm:"foreignKey:ApplicationID" json:"application,omitempty"` // Only in responses
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// Parts of an email a rule can look at
+const (
+	RuleFieldSubject = "subject"
+	RuleFieldBody    = "body"
+	RuleFieldFrom    = "from"
+	RuleFieldAny     = "any"
+)
+
+// EmailRule classifies emails whose field matches Pattern, a
+// case-insensitive regular expression. The user's rules are tried by
+// priority, lowest first, before the built-in ones.
+type EmailRule struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Field     string    `gorm:"size:10;not null;default:'any'" json:"field"` // subject, body, from or any
+	Pattern   string    `gorm:"size:500;not null" json:"pattern"`
+	Category  string    `gorm:"size:20;not null" json:"category"` // rejection, oa, interview, offer or other
+	Priority  int       `gorm:"not null;default:0" json:"priority"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EmailIngestionSettings says how the user's inbound emails are handled
+type EmailIngestionSettings struct {
+	ID     uint `gorm:"primaryKey" json:"-"`
+	UserID uint `gorm:"uniqueIndex;not null" json:"user_id"`
+	// Token identifies the user in the plus address of the shared
+	// ingestion mailbox, e.g. jobs+<token>@example.com
+	Token           string    `gorm:"uniqueIndex;size:32;not null" json:"token"`
+	AutoApply       bool      `gorm:"not null" json:"auto_apply"`        // Make status changes without asking
+	UseDefaultRules bool      `gorm:"not null" json:"use_default_rules"` // Fall back to the built-in rules
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type Interview struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	ApplicationID uint         `gorm:"index;not null" json:"application_id"`
+	UserID        uint         `gorm:"index;not null" json:"user_id"`
+	ScheduledAt   time.Time    `gorm:"index" json:"scheduled_at"`
+	Kind          string       `json:"kind"`                                                  // e.g., "Phone screen", "Technical", "Onsite"
+	Location      string       `json:"location,omitempty"`                                    // Address or meeting link
+	Note          string       `gorm:"size:1048" json:"note,omitempty"`                       // Optional note, up to 1048 chars
+	NotifiedAt    *time.Time   `json:"-"`                                                     // When the user was told it is coming up
+	Application   *Application `gorm:"foreignKey:ApplicationID" json:"application,omitempty"` // Only in responses
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
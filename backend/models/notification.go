@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+type NotificationEvent string
+
+const (
+	EventStatusChanged     NotificationEvent = "status_changed"
+	EventReminderDue       NotificationEvent = "reminder_due"
+	EventInterviewUpcoming NotificationEvent = "interview_upcoming" // Interview within the next 24 hours
+)
+
+// WebhookEventType returns the webhook event type sent for this notification event
+func (e NotificationEvent) WebhookEventType() string {
+	switch e {
+	case EventStatusChanged:
+		return WebhookStatusChanged
+	case EventReminderDue:
+		return WebhookReminderDue
+	case EventInterviewUpcoming:
+		return WebhookInterviewUpcoming
+	default:
+		return string(e)
+	}
+}
+
+// NotificationChannels says where notifications for one kind of event go
+type NotificationChannels struct {
+	Email   bool `json:"email"`
+	InApp   bool `json:"in_app"`
+	Webhook bool `json:"webhook"`
+}
+
+type NotificationSettings struct {
+	ID                uint                 `gorm:"primaryKey" json:"-"`
+	UserID            uint                 `gorm:"uniqueIndex;not null" json:"user_id"`
+	StatusChanged     NotificationChannels `gorm:"embedded;embeddedPrefix:status_changed_" json:"status_changed"`
+	ReminderDue       NotificationChannels `gorm:"embedded;embeddedPrefix:reminder_due_" json:"reminder_due"`
+	InterviewUpcoming NotificationChannels `gorm:"embedded;embeddedPrefix:interview_upcoming_" json:"interview_upcoming"`
+	QuietHoursStart   string               `json:"quiet_hours_start"` // "HH:MM" in the user's timezone, empty to disable
+	QuietHoursEnd     string               `json:"quiet_hours_end"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// DefaultNotificationSettings is used until a user saves their own settings
+func DefaultNotificationSettings(userID uint) NotificationSettings {
+	return NotificationSettings{
+		UserID:            userID,
+		StatusChanged:     NotificationChannels{InApp: true, Webhook: true},
+		ReminderDue:       NotificationChannels{InApp: true, Email: true, Webhook: true},
+		InterviewUpcoming: NotificationChannels{InApp: true, Email: true, Webhook: true},
+	}
+}
+
+// Channels returns the channels enabled for event
+func (s *NotificationSettings) Channels(event NotificationEvent) NotificationChannels {
+	switch event {
+	case EventStatusChanged:
+		return s.StatusChanged
+	case EventReminderDue:
+		return s.ReminderDue
+	case EventInterviewUpcoming:
+		return s.InterviewUpcoming
+	default:
+		return NotificationChannels{}
+	}
+}
+
+// Notification is an entry in the user's in-app notification inbox
+type Notification struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	UserID        uint              `gorm:"index;not null" json:"user_id"`
+	Event         NotificationEvent `gorm:"not null" json:"event"`
+	Title         string            `gorm:"not null" json:"title"`
+	Body          string            `gorm:"size:1048" json:"body"`
+	ApplicationID *uint             `json:"application_id,omitempty"`
+	ReadAt        *time.Time        `json:"read_at,omitempty"`
+	CreatedAt     time.Time         `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Pay periods
+const (
+	PayHourly  = "hourly"
+	PayMonthly = "monthly"
+	PayAnnual  = "annual"
+)
+
+// How likely an offer is to lead to a return offer
+const (
+	ReturnOfferUnknown  = "unknown"
+	ReturnOfferLikely   = "likely"
+	ReturnOfferPossible = "possible"
+	ReturnOfferUnlikely = "unlikely"
+)
+
+// Offer is the offer received for an application, at most one per application
+type Offer struct {
+	ID                uint         `gorm:"primaryKey" json:"id"`
+	ApplicationID     uint         `gorm:"uniqueIndex;not null" json:"application_id"`
+	UserID            uint         `gorm:"index;not null" json:"user_id"`
+	PayAmount         float64      `gorm:"not null" json:"pay_amount"`
+	PayPeriod         string       `gorm:"size:10;not null" json:"pay_period"`            // hourly, monthly or annual
+	Currency          string       `gorm:"size:3;not null;default:'USD'" json:"currency"` // ISO 4217, e.g. "USD"
+	HousingStipend    float64      `json:"housing_stipend,omitempty"`                     // Total for the internship, in Currency
+	RelocationStipend float64      `json:"relocation_stipend,omitempty"`                  // One-off, in Currency
+	StartDate         *time.Time   `json:"start_date,omitempty"`
+	EndDate           *time.Time   `json:"end_date,omitempty"`
+	Location          string       `json:"location,omitempty"`
+	DecisionDeadline  *time.Time   `gorm:"index" json:"decision_deadline,omitempty"`
+	ReturnOffer       string       `gorm:"size:10;not null;default:'unknown'" json:"return_offer"` // Return-offer potential
+	BenefitsNotes     string       `gorm:"size:2048" json:"benefits_notes,omitempty"`
+	Application       *Application `gorm:"foreignKey:ApplicationID" json:"application,omitempty"` // Only in responses
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
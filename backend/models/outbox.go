@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+	OutboxDead    OutboxStatus = "dead" // Gave up after too many failed attempts
+)
+
+// OutboxEmail is an outgoing email written in the same transaction as the
+// change that triggered it and delivered later by the outbox worker.
+type OutboxEmail struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	Recipient     string            `gorm:"not null" json:"recipient"`
+	Subject       string            `gorm:"not null" json:"subject"`
+	Body          string            `gorm:"type:text;not null" json:"body"`
+	Headers       map[string]string `gorm:"type:text;serializer:json" json:"headers,omitempty"` // Extra headers, e.g. List-Unsubscribe
+	Status        OutboxStatus      `gorm:"index;not null;default:'pending'" json:"status"`
+	Attempts      int               `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time         `gorm:"index" json:"next_attempt_at"`
+	LastError     string            `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt        *time.Time        `json:"sent_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Reminder struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	UserID        uint         `gorm:"index;not null" json:"user_id"`
+	ApplicationID *uint        `gorm:"index" json:"application_id,omitempty"` // Optional, reminders can be standalone
+	Title         string       `gorm:"not null" json:"title"`
+	DueAt         time.Time    `gorm:"index" json:"due_at"`
+	CompletedAt   *time.Time   `json:"completed_at,omitempty"`
+	NotifiedAt    *time.Time   `json:"-"`                                                     // When the user was notified that it is due
+	Application   *Application `gorm:"foreignKey:ApplicationID" json:"application,omitempty"` // Only in responses
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Tag is a user-defined label for grouping applications, e.g. "referral"
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_tag_user_name;not null" json:"user_id"`
+	Name      string    `gorm:"uniqueIndex:idx_tag_user_name;size:50;not null" json:"name"`
+	Color     string    `gorm:"size:7;not null;default:'#6b7280'" json:"color"` // Hex, e.g. "#ff8800"
+	CreatedAt time.Time `json:"created_at"`
+}
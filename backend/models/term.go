@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Recruiting seasons, in calendar order
+const (
+	SeasonWinter = "Winter"
+	SeasonSpring = "Spring"
+	SeasonSummer = "Summer"
+	SeasonFall   = "Fall"
+)
+
+// Seasons lists the seasons in calendar order
+var Seasons = []string{SeasonWinter, SeasonSpring, SeasonSummer, SeasonFall}
+
+// Term is a normalized recruiting term like "Summer 2025". Terms are shared
+// by all users and created the first time an application uses them.
+type Term struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Season    string    `gorm:"uniqueIndex:idx_term_season_year;size:10;not null" json:"season"`
+	Year      int       `gorm:"uniqueIndex:idx_term_season_year;not null" json:"year"`
+	Name      string    `gorm:"size:20;not null" json:"name"` // e.g. "Summer 2025"
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -8,14 +8,34 @@ type User struct {
 	Email      string    `gorm:"unique;not null" json:"email"`
 	Password   string    `json:"-"`
 	IsVerified bool      `gorm:"default:false" json:"is_verified"`
+	IsAdmin    bool      `gorm:"default:false" json:"is_admin"`
+	Timezone   string    `gorm:"default:'UTC'" json:"timezone"` // IANA name, e.g. "America/New_York"
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+
+	WeeklyDigest     bool       `gorm:"default:false" json:"weekly_digest"` // Opt-in weekly progress email
+	LastDigestSentAt *time.Time `json:"-"`
+
+	ActiveTermID *uint `json:"active_term_id"` // Term new applications default to, and ?term=active
+
+	// SessionVersion is embedded in session tokens, bumping it signs out every
+	// session issued before
+	SessionVersion      uint       `gorm:"not null;default:0" json:"-"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"` // Account is purged after this, unless restored
 }
 
+// Verification purposes
+const (
+	VerificationSignup      = "signup"
+	VerificationEmailChange = "email_change"
+)
+
 type EmailVerification struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	UserID    uint      `gorm:"not null" json:"user_id"`
 	Token     string    `gorm:"not null" json:"token"`
+	Purpose   string    `gorm:"not null;default:'signup'" json:"purpose"`
+	NewEmail  string    `json:"new_email,omitempty"` // Address to switch to, for email changes
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Webhook event types, in addition to the application.* events shared with
+// the live event stream
+const (
+	WebhookStatusChanged      = "status.changed"
+	WebhookInterviewScheduled = "interview.scheduled"
+	WebhookInterviewUpcoming  = "interview.upcoming"
+	WebhookReminderDue        = "reminder.due"
+	WebhookTest               = "webhook.test" // Only sent by the "send test event" endpoint
+)
+
+// WebhookEventTypes lists the event types endpoints can subscribe to
+var WebhookEventTypes = []string{
+	EventApplicationCreated,
+	EventApplicationUpdated,
+	EventApplicationDeleted,
+	WebhookStatusChanged,
+	WebhookInterviewScheduled,
+	WebhookInterviewUpcoming,
+	WebhookReminderDue,
+}
+
+type WebhookEndpoint struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"index;not null" json:"user_id"`
+	URL         string    `gorm:"not null" json:"url"`
+	Description string    `json:"description,omitempty"`
+	Secret      string    `gorm:"not null" json:"-"` // Used to sign payloads, only shown when created or rotated
+	EventTypes  []string  `gorm:"type:text;serializer:json" json:"event_types"`
+	Active      bool      `gorm:"default:true" json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Subscribes reports whether the endpoint wants events of eventType
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	for _, t := range e.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookPending   WebhookDeliveryStatus = "pending"
+	WebhookSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDead      WebhookDeliveryStatus = "dead" // Gave up after too many failed attempts
+)
+
+// WebhookDelivery is one event sent (or to be sent) to an endpoint, kept as
+// the endpoint's delivery log
+type WebhookDelivery struct {
+	ID            uint                  `gorm:"primaryKey" json:"id"`
+	EndpointID    uint                  `gorm:"index;not null" json:"endpoint_id"`
+	UserID        uint                  `gorm:"index;not null" json:"user_id"`
+	EventType     string                `gorm:"not null" json:"event_type"`
+	Payload       string                `gorm:"type:text;not null" json:"payload"`
+	Status        WebhookDeliveryStatus `gorm:"index;not null;default:'pending'" json:"status"`
+	Attempts      int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time             `gorm:"index" json:"next_attempt_at"`
+	ResponseCode  int                   `json:"response_code,omitempty"`
+	ResponseBody  string                `gorm:"type:text" json:"response_body,omitempty"` // Truncated
+	LastError     string                `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt   *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Wishlist priorities
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+)
+
+// WishlistItem is a posting the user intends to apply to. Converting it
+// creates an application and removes the item.
+type WishlistItem struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"index;not null" json:"user_id"`
+	Company     string     `gorm:"not null" json:"company"`
+	Position    string     `gorm:"not null" json:"position"`
+	Location    string     `json:"location,omitempty"`
+	Term        string     `json:"term,omitempty"` // e.g., "Summer 2025", normalized when it can be parsed
+	PostingURL  string     `json:"posting_url,omitempty"`
+	Description string     `gorm:"type:text" json:"description,omitempty"`
+	Deadline    *time.Time `gorm:"index" json:"deadline,omitempty"`                   // Last day to apply
+	DatePosted  *time.Time `json:"date_posted,omitempty"`                             // When the posting went up, if known
+	Priority    string     `gorm:"size:10;not null;default:'medium'" json:"priority"` // low, medium or high
+	Note        string     `gorm:"size:1048" json:"note,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// PersonalAccessTokenPrefix marks personal access tokens so CheckAuth can
+// tell them apart from JWTs without trying to parse them
+const PersonalAccessTokenPrefix = "ihp_"
+
+// GeneratePersonalAccessToken returns a new random token and the hash to store for it
+func GeneratePersonalAccessToken() (string, string) {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+	token := PersonalAccessTokenPrefix + hex.EncodeToString(bytes)
+	return token, HashAccessToken(token)
+}
+
+// HashAccessToken hashes a token for storage and lookup. The tokens are long
+// and random, so a plain SHA-256 is enough and keeps lookups by hash possible.
+func HashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
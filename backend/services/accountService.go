@@ -0,0 +1,142 @@
+package services
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AccountDeletionGracePeriod is how long a deleted account can still be restored
+func AccountDeletionGracePeriod() time.Duration {
+	return config.GetEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", 7*24*time.Hour)
+}
+
+// userOwnedModels are deleted with the account, children before parents
+var userOwnedModels = []interface{}{
+	&models.ApplicationStatusChange{},
+	&models.Interview{},
+	&models.Reminder{},
+	&models.Offer{},
+	&models.WishlistItem{},
+	&models.InboundEmail{},
+	&models.EmailRule{},
+	&models.EmailIngestionSettings{},
+	&models.AutomationExecution{},
+	&models.AutomationRule{},
+	&models.Notification{},
+	&models.NotificationSettings{},
+	&models.ApplicationEvent{},
+	&models.WebhookDelivery{},
+	&models.WebhookEndpoint{},
+	&models.PersonalAccessToken{},
+	&models.UserIdentity{},
+	&models.EmailVerification{},
+	&models.DataExport{},
+	&models.Application{},
+	&models.Tag{},
+	&models.Company{},
+}
+
+// DeleteUserData removes the user and everything they own as part of tx. It
+// returns the resume and export files to remove once tx has committed.
+func DeleteUserData(tx *gorm.DB, userID uint) ([]string, error) {
+	var resumeURLs []string
+	if err := tx.Unscoped().Model(&models.Application{}).Where("user_id = ? AND resume_url <> ''", userID).
+		Pluck("resume_url", &resumeURLs).Error; err != nil {
+		return nil, err
+	}
+	var exportFiles []string
+	if err := tx.Model(&models.DataExport{}).Where("user_id = ? AND file_path <> ''", userID).
+		Pluck("file_path", &exportFiles).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Exec("DELETE FROM application_tags WHERE tag_id IN (SELECT id FROM tags WHERE user_id = ?)", userID).Error; err != nil {
+		return nil, err
+	}
+	for _, model := range userOwnedModels {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Delete(&models.User{}, userID).Error; err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(resumeURLs))
+	for _, resumeURL := range resumeURLs {
+		files = append(files, "."+resumeURL)
+	}
+	return append(files, exportFiles...), nil
+}
+
+// RemoveFiles deletes stored files, ignoring ones that are already gone
+func RemoveFiles(paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove file %s: %v", path, err)
+		}
+	}
+}
+
+// StartAccountDeletionWorker purges accounts whose deletion grace period has ended
+func StartAccountDeletionWorker() {
+	interval := config.GetEnvDuration("ACCOUNT_DELETION_CHECK_INTERVAL", time.Hour)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for {
+				purged, err := purgeNextAccount()
+				if err != nil {
+					log.Printf("Accounts: failed to purge deleted account: %v", err)
+				}
+				if err != nil || !purged {
+					break
+				}
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+func purgeNextAccount() (bool, error) {
+	var files []string
+	purged := false
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", time.Now()).
+			Limit(1).
+			Find(&user)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var err error
+		files, err = DeleteUserData(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		purged = true
+		log.Printf("Accounts: purged user %d", user.ID)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	RemoveFiles(files)
+	return purged, nil
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+)
+
+// SeasonStats summarizes the applications of one term
+type SeasonStats struct {
+	Term         string         `json:"term"`
+	Season       string         `json:"season"`
+	Year         int            `json:"year,omitempty"`
+	Archived     bool           `json:"archived"` // Every application of the term is archived
+	Total        int            `json:"total"`
+	ByStatus     map[string]int `json:"by_status"`
+	ResponseRate float64        `json:"response_rate"` // Share that heard back (anything past Applied)
+	OfferRate    float64        `json:"offer_rate"`
+}
+
+// SeasonChange compares a season with the same season of an earlier year
+type SeasonChange struct {
+	FromYear           int     `json:"from_year"`
+	ToYear             int     `json:"to_year"`
+	TotalChange        int     `json:"total_change"`
+	ResponseRateChange float64 `json:"response_rate_change"`
+	OfferRateChange    float64 `json:"offer_rate_change"`
+}
+
+// SeasonTrend is one season (e.g. "Summer") across years, oldest first
+type SeasonTrend struct {
+	Season  string         `json:"season"`
+	Years   []SeasonStats  `json:"years"`
+	Changes []SeasonChange `json:"changes"`
+}
+
+type SeasonAnalytics struct {
+	Terms        []SeasonStats `json:"terms"`
+	YearOverYear []SeasonTrend `json:"year_over_year"`
+}
+
+var termYear = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+
+// splitTerm splits a term like "Summer 2025" into its season and year.
+// Terms ParseTerm doesn't know are split around their year.
+func splitTerm(term string) (string, int) {
+	if season, year, ok := ParseTerm(term); ok {
+		return season, year
+	}
+
+	term = strings.TrimSpace(term)
+	match := termYear.FindString(term)
+	if match == "" {
+		return term, 0
+	}
+	year, _ := strconv.Atoi(match)
+	season := strings.Join(strings.Fields(strings.Replace(term, match, "", 1)), " ")
+	if season != "" {
+		season = strings.ToUpper(season[:1]) + strings.ToLower(season[1:])
+	}
+	return season, year
+}
+
+// BuildSeasonAnalytics groups applications by term and compares each season
+// with itself in other years
+func BuildSeasonAnalytics(apps []models.Application) SeasonAnalytics {
+	byTerm := make(map[string]*SeasonStats)
+	for _, app := range apps {
+		season, year := splitTerm(app.Term)
+		key := strings.ToLower(season) + "|" + strconv.Itoa(year)
+
+		stats := byTerm[key]
+		if stats == nil {
+			label := season
+			if year != 0 {
+				label = strings.TrimSpace(season + " " + strconv.Itoa(year))
+			}
+			stats = &SeasonStats{Term: label, Season: season, Year: year, Archived: true, ByStatus: map[string]int{}}
+			byTerm[key] = stats
+		}
+		stats.Total++
+		stats.ByStatus[app.Status.String()]++
+		if !app.Archived {
+			stats.Archived = false
+		}
+	}
+
+	analytics := SeasonAnalytics{Terms: []SeasonStats{}, YearOverYear: []SeasonTrend{}}
+	trends := make(map[string]*SeasonTrend)
+	for _, stats := range byTerm {
+		responded := stats.Total - stats.ByStatus[models.StatusApplied.String()]
+		stats.ResponseRate = rate(responded, stats.Total)
+		stats.OfferRate = rate(stats.ByStatus[models.StatusAccepted.String()], stats.Total)
+		analytics.Terms = append(analytics.Terms, *stats)
+
+		if stats.Year == 0 {
+			continue
+		}
+		key := strings.ToLower(stats.Season)
+		if trends[key] == nil {
+			trends[key] = &SeasonTrend{Season: stats.Season}
+		}
+		trends[key].Years = append(trends[key].Years, *stats)
+	}
+
+	// Newest terms first
+	sort.Slice(analytics.Terms, func(i, j int) bool {
+		if analytics.Terms[i].Year != analytics.Terms[j].Year {
+			return analytics.Terms[i].Year > analytics.Terms[j].Year
+		}
+		return analytics.Terms[i].Season < analytics.Terms[j].Season
+	})
+
+	for _, trend := range trends {
+		sort.Slice(trend.Years, func(i, j int) bool { return trend.Years[i].Year < trend.Years[j].Year })
+		trend.Changes = []SeasonChange{}
+		for i := 1; i < len(trend.Years); i++ {
+			from, to := trend.Years[i-1], trend.Years[i]
+			trend.Changes = append(trend.Changes, SeasonChange{
+				FromYear:           from.Year,
+				ToYear:             to.Year,
+				TotalChange:        to.Total - from.Total,
+				ResponseRateChange: roundRate(to.ResponseRate - from.ResponseRate),
+				OfferRateChange:    roundRate(to.OfferRate - from.OfferRate),
+			})
+		}
+		analytics.YearOverYear = append(analytics.YearOverYear, *trend)
+	}
+	sort.Slice(analytics.YearOverYear, func(i, j int) bool {
+		return analytics.YearOverYear[i].Season < analytics.YearOverYear[j].Season
+	})
+
+	return analytics
+}
+
+// OutcomeStats counts applications by status, with the share that heard
+// back and the share that got an offer
+type OutcomeStats struct {
+	Total        int            `json:"total"`
+	ByStatus     map[string]int `json:"by_status"`
+	ResponseRate float64        `json:"response_rate"` // Share that heard back (anything past Applied)
+	OfferRate    float64        `json:"offer_rate"`
+}
+
+func newOutcomeStats() OutcomeStats {
+	return OutcomeStats{ByStatus: map[string]int{}}
+}
+
+func (s *OutcomeStats) add(app *models.Application) {
+	s.Total++
+	s.ByStatus[app.Status.String()]++
+}
+
+func (s *OutcomeStats) computeRates() {
+	responded := s.Total - s.ByStatus[models.StatusApplied.String()]
+	s.ResponseRate = rate(responded, s.Total)
+	s.OfferRate = rate(s.ByStatus[models.StatusAccepted.String()], s.Total)
+}
+
+// LocationStats summarizes the applications with one work mode, or in one
+// country or city
+type LocationStats struct {
+	Value string `json:"value"`
+	OutcomeStats
+}
+
+type LocationAnalytics struct {
+	WorkModes []LocationStats `json:"work_modes"`
+	Countries []LocationStats `json:"countries"`
+	Cities    []LocationStats `json:"cities"`
+}
+
+// BuildLocationAnalytics compares outcomes by work mode, country and city.
+// Applications without that part of their location are left out of it.
+func BuildLocationAnalytics(apps []models.Application) LocationAnalytics {
+	return LocationAnalytics{
+		WorkModes: locationStats(apps, func(app *models.Application) string { return string(app.WorkMode) }),
+		Countries: locationStats(apps, func(app *models.Application) string { return app.Country }),
+		Cities:    locationStats(apps, func(app *models.Application) string { return app.City }),
+	}
+}
+
+func locationStats(apps []models.Application, value func(*models.Application) string) []LocationStats {
+	byValue := make(map[string]*LocationStats)
+	for i := range apps {
+		v := value(&apps[i])
+		if v == "" {
+			continue
+		}
+		stats := byValue[v]
+		if stats == nil {
+			stats = &LocationStats{Value: v, OutcomeStats: newOutcomeStats()}
+			byValue[v] = stats
+		}
+		stats.add(&apps[i])
+	}
+
+	result := make([]LocationStats, 0, len(byValue))
+	for _, stats := range byValue {
+		stats.computeRates()
+		result = append(result, *stats)
+	}
+
+	// Most applications first
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+func rate(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return roundRate(float64(count) / float64(total))
+}
+
+// roundRate rounds to 4 decimals so rates read well in JSON
+func roundRate(r float64) float64 {
+	return math.Round(r*10000) / 10000
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AuthClaims are the claims of the session JWTs issued on login
+type AuthClaims struct {
+	UserID         uint `json:"id"`
+	SessionVersion uint `json:"sv,omitempty"` // Must match User.SessionVersion
+	jwt.RegisteredClaims
+}
+
+// signingKey is one key from JWT_KEYS_DIR. Keys without a private half can
+// only verify tokens, which is how old keys are kept around after rotation.
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+type keySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+var (
+	keysMu      sync.RWMutex
+	currentKeys = &keySet{keys: map[string]*signingKey{}}
+)
+
+// legacyKeyID is the kid used for HS256 tokens signed with JWT_SECRET
+const legacyKeyID = "hs256"
+
+// LoadSigningKeys loads the JWT keys from JWT_KEYS_DIR, one PEM file per key
+// named <kid>.pem, and signs with JWT_ACTIVE_KEY_ID. Without JWT_KEYS_DIR
+// tokens are signed with HS256 and JWT_SECRET, which is fine for development.
+func LoadSigningKeys() error {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		keysMu.Lock()
+		currentKeys = &keySet{keys: map[string]*signingKey{}}
+		keysMu.Unlock()
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	set := &keySet{keys: map[string]*signingKey{}}
+	for _, file := range files {
+		key, err := loadSigningKey(file)
+		if err != nil {
+			return fmt.Errorf("failed to load JWT key %s: %w", file, err)
+		}
+		set.keys[key.id] = key
+	}
+
+	activeID := os.Getenv("JWT_ACTIVE_KEY_ID")
+	set.active = set.keys[activeID]
+	if set.active == nil || set.active.private == nil {
+		return fmt.Errorf("JWT_ACTIVE_KEY_ID %q must name a private key in %s", activeID, dir)
+	}
+
+	keysMu.Lock()
+	currentKeys = set
+	keysMu.Unlock()
+	return nil
+}
+
+// StartSigningKeyReloader reloads the JWT keys periodically, so keys can be
+// rotated on every replica without a restart
+func StartSigningKeyReloader() {
+	if os.Getenv("JWT_KEYS_DIR") == "" {
+		return
+	}
+	interval := config.GetEnvDuration("JWT_KEYS_RELOAD_INTERVAL", 5*time.Minute)
+
+	go func() {
+		for range time.Tick(interval) {
+			if err := LoadSigningKeys(); err != nil {
+				log.Printf("JWT: failed to reload signing keys, keeping the current ones: %v", err)
+			}
+		}
+	}()
+}
+
+func loadSigningKey(file string) (*signingKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	key := &signingKey{id: strings.TrimSuffix(filepath.Base(file), ".pem")}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		key.private = signer
+		key.public = signer.Public()
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.private = parsed
+		key.public = parsed.Public()
+	case "PUBLIC KEY":
+		key.public, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+
+	switch key.public.(type) {
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	default:
+		return nil, errors.New("only Ed25519 and RSA keys are supported")
+	}
+	return key, nil
+}
+
+// IssueAuthToken signs a 24-hour session token for the user with the active key
+func IssueAuthToken(user *models.User) (string, error) {
+	now := time.Now()
+	claims := AuthClaims{
+		UserID:         user.ID,
+		SessionVersion: user.SessionVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    config.GetJWTIssuer(),
+			Audience:  jwt.ClaimStrings{config.GetJWTAudience()},
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
+		},
+	}
+
+	keysMu.RLock()
+	active := currentKeys.active
+	keysMu.RUnlock()
+
+	if active == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Header["kid"] = legacyKeyID
+		return token.SignedString([]byte(config.GetJWTSecret()))
+	}
+
+	token := jwt.NewWithClaims(active.method, claims)
+	token.Header["kid"] = active.id
+	return token.SignedString(active.private)
+}
+
+// ParseAuthToken verifies a session token against every key that hasn't
+// been retired and returns its claims
+func ParseAuthToken(tokenString string) (*AuthClaims, error) {
+	keysMu.RLock()
+	set := currentKeys
+	keysMu.RUnlock()
+
+	// HS256 is accepted when it is what we sign with, or while migrating
+	// away from it so existing sessions don't all end at once
+	acceptHS256 := set.active == nil || config.GetEnvBool("JWT_ACCEPT_HS256", false)
+
+	claims := &AuthClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+				if !acceptHS256 {
+					return nil, errors.New("HS256 tokens are no longer accepted")
+				}
+				return []byte(config.GetJWTSecret()), nil
+			}
+
+			kid, _ := token.Header["kid"].(string)
+			key, ok := set.keys[kid]
+			if !ok {
+				return nil, fmt.Errorf("unknown signing key %q", kid)
+			}
+			if token.Method.Alg() != key.method.Alg() {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
+			}
+			return key.public, nil
+		},
+		jwt.WithValidMethods([]string{"EdDSA", "RS256", "HS256"}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	// Tokens issued before issuer/audience were added are plain HS256 tokens
+	// without them. Everything else must be meant for us.
+	legacy := token.Method.Alg() == "HS256" && claims.Issuer == ""
+	if !legacy {
+		if claims.Issuer != config.GetJWTIssuer() {
+			return nil, errors.New("invalid token issuer")
+		}
+		if !containsString(claims.Audience, config.GetJWTAudience()) {
+			return nil, errors.New("invalid token audience")
+		}
+	}
+	if claims.UserID == 0 {
+		return nil, errors.New("token has no user")
+	}
+
+	return claims, nil
+}
+
+// PublicJWKS returns the public keys that tokens may be signed with, for
+// /.well-known/jwks.json
+func PublicJWKS() []JWK {
+	keysMu.RLock()
+	set := currentKeys
+	keysMu.RUnlock()
+
+	ids := make([]string, 0, len(set.keys))
+	for id := range set.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	jwks := []JWK{}
+	for _, id := range ids {
+		jwk, err := NewJWK(id, set.keys[id].public)
+		if err != nil {
+			continue
+		}
+		jwks = append(jwks, jwk)
+	}
+	return jwks
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,531 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Fields automation conditions can look at. Text fields are compared
+// case-insensitively; tag is eq when the application has the tag.
+var automationTextFields = []string{"company", "position", "location", "term", "country", "work_mode", "note", "tag"}
+var automationNumberFields = []string{"days_in_status", "days_since_applied", "days_since_created"}
+
+// AutomationMatch is an application a rule acts on, with what it does
+type AutomationMatch struct {
+	ApplicationID uint      `json:"application_id"`
+	Company       string    `json:"company"`
+	Position      string    `json:"position"`
+	TriggerKey    string    `json:"trigger_key"`
+	TriggeredAt   time.Time `json:"triggered_at"`
+	Actions       []string  `json:"actions"`
+
+	app *models.Application
+}
+
+// automationFacts is an application with what conditions need to know about it
+type automationFacts struct {
+	app            *models.Application
+	statusChangeID uint      // Latest status change not made by automation, 0 if there is none
+	statusSince    time.Time // When it got its current status
+}
+
+// ValidateAutomationRule checks a rule's trigger, conditions and actions
+func ValidateAutomationRule(rule *models.AutomationRule) error {
+	switch rule.Trigger {
+	case models.TriggerStatusChanged, models.TriggerCreated:
+	case models.TriggerSchedule:
+		if rule.DelayDays != 0 {
+			return errors.New("scheduled rules can't have a delay, use a days_in_status condition")
+		}
+	default:
+		return errors.New("trigger must be status_changed, created or schedule")
+	}
+	if rule.TriggerStatus != nil && (rule.Trigger != models.TriggerStatusChanged || *rule.TriggerStatus > models.StatusRejected) {
+		return errors.New("trigger_status must be a valid status, for status_changed rules")
+	}
+	if rule.DelayDays < 0 || rule.DelayDays > 365 {
+		return errors.New("delay_days must be between 0 and 365")
+	}
+
+	for _, condition := range rule.Conditions {
+		if err := validateAutomationCondition(condition); err != nil {
+			return err
+		}
+	}
+
+	if len(rule.Actions) == 0 {
+		return errors.New("at least one action is required")
+	}
+	for _, action := range rule.Actions {
+		switch action.Type {
+		case models.ActionAddTag, models.ActionRemoveTag:
+			if strings.TrimSpace(action.Tag) == "" || len(action.Tag) > 50 {
+				return fmt.Errorf("%s needs a tag of up to 50 characters", action.Type)
+			}
+		case models.ActionSetStatus:
+			if action.Status == nil || *action.Status > models.StatusRejected {
+				return errors.New("set_status needs a valid status")
+			}
+		case models.ActionArchive:
+		case models.ActionCreateReminder:
+			if action.Days < 0 || action.Days > 365 {
+				return errors.New("create_reminder days must be between 0 and 365")
+			}
+		default:
+			return errors.New("unknown action: " + action.Type)
+		}
+	}
+	return nil
+}
+
+func validateAutomationCondition(condition models.AutomationCondition) error {
+	switch {
+	case condition.Field == "status":
+		if _, ok := parseAutomationStatus(condition.Value); !ok {
+			return errors.New("unknown status " + condition.Value)
+		}
+		if condition.Op != models.ConditionEq && condition.Op != models.ConditionNeq {
+			return errors.New("status conditions must use eq or neq")
+		}
+	case condition.Field == "archived":
+		if _, err := strconv.ParseBool(condition.Value); err != nil {
+			return errors.New("archived conditions need true or false")
+		}
+		if condition.Op != models.ConditionEq && condition.Op != models.ConditionNeq {
+			return errors.New("archived conditions must use eq or neq")
+		}
+	case containsString(automationTextFields, condition.Field):
+		if condition.Op != models.ConditionEq && condition.Op != models.ConditionNeq && condition.Op != models.ConditionContains {
+			return fmt.Errorf("%s conditions must use eq, neq or contains", condition.Field)
+		}
+	case containsString(automationNumberFields, condition.Field):
+		if _, err := strconv.Atoi(condition.Value); err != nil {
+			return fmt.Errorf("%s conditions need a number of days", condition.Field)
+		}
+		switch condition.Op {
+		case models.ConditionEq, models.ConditionNeq, models.ConditionGt, models.ConditionGte, models.ConditionLt, models.ConditionLte:
+		default:
+			return fmt.Errorf("%s conditions must use eq, neq, gt, gte, lt or lte", condition.Field)
+		}
+	default:
+		return errors.New("unknown condition field: " + condition.Field)
+	}
+	return nil
+}
+
+// parseAutomationStatus reads a status by number or name, e.g. "4" or "Rejected"
+func parseAutomationStatus(value string) (models.ApplicationStatus, bool) {
+	if n, err := strconv.Atoi(value); err == nil {
+		return models.ApplicationStatus(n), n >= int(models.StatusApplied) && n <= int(models.StatusRejected)
+	}
+	for status := models.StatusApplied; status <= models.StatusRejected; status++ {
+		if strings.EqualFold(status.String(), strings.TrimSpace(value)) {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// automationConditionHolds reports whether the application meets one condition
+func automationConditionHolds(facts *automationFacts, condition models.AutomationCondition, now time.Time) bool {
+	app := facts.app
+	var equal bool
+
+	switch condition.Field {
+	case "status":
+		status, _ := parseAutomationStatus(condition.Value)
+		equal = app.Status == status
+	case "archived":
+		archived, _ := strconv.ParseBool(condition.Value)
+		equal = app.Archived == archived
+	case "tag":
+		for _, tag := range app.Tags {
+			if condition.Op == models.ConditionContains {
+				equal = equal || strings.Contains(strings.ToLower(tag.Name), strings.ToLower(condition.Value))
+			} else {
+				equal = equal || strings.EqualFold(tag.Name, condition.Value)
+			}
+		}
+		if condition.Op == models.ConditionNeq {
+			return !equal
+		}
+		return equal
+	case "days_in_status", "days_since_applied", "days_since_created":
+		since := facts.statusSince
+		if condition.Field == "days_since_applied" {
+			since = app.AppliedDate
+		} else if condition.Field == "days_since_created" {
+			since = app.CreatedAt
+		}
+		days := int(now.Sub(since).Hours() / 24)
+		value, _ := strconv.Atoi(condition.Value)
+		switch condition.Op {
+		case models.ConditionGt:
+			return days > value
+		case models.ConditionGte:
+			return days >= value
+		case models.ConditionLt:
+			return days < value
+		case models.ConditionLte:
+			return days <= value
+		}
+		equal = days == value
+	default:
+		text := map[string]string{
+			"company":   app.Company,
+			"position":  app.Position,
+			"location":  app.Location,
+			"term":      app.Term,
+			"country":   app.Country,
+			"work_mode": string(app.WorkMode),
+			"note":      app.Note,
+		}[condition.Field]
+		if condition.Op == models.ConditionContains {
+			return strings.Contains(strings.ToLower(text), strings.ToLower(condition.Value))
+		}
+		equal = strings.EqualFold(strings.TrimSpace(text), strings.TrimSpace(condition.Value))
+	}
+
+	if condition.Op == models.ConditionNeq {
+		return !equal
+	}
+	return equal
+}
+
+// loadAutomationFacts loads the user's applications, outside the trash,
+// with their tags and latest status changes
+func loadAutomationFacts(db *gorm.DB, userID uint) (map[uint]*automationFacts, error) {
+	var apps []models.Application
+	if err := db.Preload("Tags").Where("user_id = ?", userID).Find(&apps).Error; err != nil {
+		return nil, err
+	}
+	latest := func(query *gorm.DB) ([]models.ApplicationStatusChange, error) {
+		var changes []models.ApplicationStatusChange
+		err := query.Select("DISTINCT ON (application_id) id, application_id, created_at").
+			Where("user_id = ?", userID).Order("application_id, id DESC").Find(&changes).Error
+		return changes, err
+	}
+	changes, err := latest(db)
+	if err != nil {
+		return nil, err
+	}
+	// Scheduled rules act once per status the user (or an import, or an
+	// email) gave the application, so rules can't keep each other going
+	manual, err := latest(db.Where("automated = ?", false))
+	if err != nil {
+		return nil, err
+	}
+
+	facts := make(map[uint]*automationFacts, len(apps))
+	for i := range apps {
+		facts[apps[i].ID] = &automationFacts{app: &apps[i], statusSince: apps[i].CreatedAt}
+	}
+	for _, change := range changes {
+		if f := facts[change.ApplicationID]; f != nil {
+			f.statusSince = change.CreatedAt
+		}
+	}
+	for _, change := range manual {
+		if f := facts[change.ApplicationID]; f != nil {
+			f.statusChangeID = change.ID
+		}
+	}
+	return facts, nil
+}
+
+// EvaluateAutomationRule returns the applications the rule would act on at
+// now. Events after since are considered once their delay has passed;
+// scheduled rules look at every application. Trigger keys the rule has
+// already acted on are left out, and status changes made by rules don't
+// trigger rules.
+func EvaluateAutomationRule(db *gorm.DB, rule *models.AutomationRule, since, now time.Time) ([]AutomationMatch, error) {
+	facts, err := loadAutomationFacts(db, rule.UserID)
+	if err != nil {
+		return nil, err
+	}
+	due := now.AddDate(0, 0, -rule.DelayDays)
+
+	type candidate struct {
+		facts       *automationFacts
+		key         string
+		triggeredAt time.Time
+	}
+	var candidates []candidate
+
+	switch rule.Trigger {
+	case models.TriggerStatusChanged:
+		query := db.Where("user_id = ? AND automated = ? AND created_at > ? AND created_at <= ?", rule.UserID, false, since, due)
+		if rule.TriggerStatus != nil {
+			query = query.Where("to_status = ?", *rule.TriggerStatus)
+		}
+		var changes []models.ApplicationStatusChange
+		if err := query.Order("id ASC").Find(&changes).Error; err != nil {
+			return nil, err
+		}
+		for _, change := range changes {
+			if f := facts[change.ApplicationID]; f != nil {
+				candidates = append(candidates, candidate{f, fmt.Sprintf("status_change:%d", change.ID), change.CreatedAt})
+			}
+		}
+	case models.TriggerCreated:
+		for _, f := range facts {
+			if f.app.CreatedAt.After(since) && !f.app.CreatedAt.After(due) {
+				candidates = append(candidates, candidate{f, "created", f.app.CreatedAt})
+			}
+		}
+	case models.TriggerSchedule:
+		for _, f := range facts {
+			candidates = append(candidates, candidate{f, fmt.Sprintf("schedule:%d", f.statusChangeID), now})
+		}
+	}
+
+	executed := map[string]bool{}
+	if rule.ID != 0 {
+		var executions []models.AutomationExecution
+		if err := db.Select("application_id, trigger_key").Where("rule_id = ?", rule.ID).Find(&executions).Error; err != nil {
+			return nil, err
+		}
+		for _, execution := range executions {
+			executed[fmt.Sprintf("%d/%s", execution.ApplicationID, execution.TriggerKey)] = true
+		}
+	}
+
+	matches := []AutomationMatch{}
+	for _, candidate := range candidates {
+		if executed[fmt.Sprintf("%d/%s", candidate.facts.app.ID, candidate.key)] {
+			continue
+		}
+		holds := true
+		for _, condition := range rule.Conditions {
+			holds = holds && automationConditionHolds(candidate.facts, condition, now)
+		}
+		if !holds {
+			continue
+		}
+
+		app := candidate.facts.app
+		match := AutomationMatch{
+			ApplicationID: app.ID,
+			Company:       app.Company,
+			Position:      app.Position,
+			TriggerKey:    candidate.key,
+			TriggeredAt:   candidate.triggeredAt,
+			app:           app,
+		}
+		for _, action := range rule.Actions {
+			match.Actions = append(match.Actions, describeAutomationAction(action, app, now))
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+func reminderTitle(action models.AutomationAction, app *models.Application) string {
+	title := action.Title
+	if title == "" {
+		title = "Follow up with {company}"
+	}
+	return strings.NewReplacer("{company}", app.Company, "{position}", app.Position).Replace(title)
+}
+
+// describeAutomationAction says what an action does to app, for previews
+// and the execution log
+func describeAutomationAction(action models.AutomationAction, app *models.Application, now time.Time) string {
+	switch action.Type {
+	case models.ActionAddTag:
+		return fmt.Sprintf("Add tag %q", action.Tag)
+	case models.ActionRemoveTag:
+		return fmt.Sprintf("Remove tag %q", action.Tag)
+	case models.ActionSetStatus:
+		return fmt.Sprintf("Set status to %s", *action.Status)
+	case models.ActionArchive:
+		return "Archive"
+	case models.ActionCreateReminder:
+		return fmt.Sprintf("Create reminder %q due %s", reminderTitle(action, app), now.AddDate(0, 0, action.Days).Format("2006-01-02"))
+	}
+	return action.Type
+}
+
+// runAutomationActions runs the rule's actions on the application as part of tx
+func runAutomationActions(tx *gorm.DB, rule *models.AutomationRule, app *models.Application, now time.Time) error {
+	tagsChanged := false
+	for _, action := range rule.Actions {
+		switch action.Type {
+		case models.ActionAddTag:
+			var tag models.Tag
+			err := tx.Where("user_id = ? AND LOWER(name) = ?", rule.UserID, strings.ToLower(strings.TrimSpace(action.Tag))).First(&tag).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				tag = models.Tag{UserID: rule.UserID, Name: strings.TrimSpace(action.Tag)}
+				err = tx.Create(&tag).Error
+			}
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(app).Association("Tags").Append(&tag); err != nil {
+				return err
+			}
+			tagsChanged = true
+
+		case models.ActionRemoveTag:
+			var tag models.Tag
+			if err := tx.Where("user_id = ? AND LOWER(name) = ?", rule.UserID, strings.ToLower(strings.TrimSpace(action.Tag))).
+				First(&tag).Error; err != nil {
+				continue // Nothing to remove
+			}
+			if err := tx.Model(app).Association("Tags").Delete(&tag); err != nil {
+				return err
+			}
+			tagsChanged = true
+
+		case models.ActionSetStatus:
+			if err := setApplicationStatus(tx, app, *action.Status, true); err != nil {
+				return err
+			}
+
+		case models.ActionArchive:
+			if !app.Archived {
+				if err := SetArchived(tx, app, true); err != nil {
+					return err
+				}
+			}
+
+		case models.ActionCreateReminder:
+			reminder := models.Reminder{
+				UserID:        rule.UserID,
+				ApplicationID: &app.ID,
+				Title:         reminderTitle(action, app),
+				DueAt:         now.AddDate(0, 0, action.Days),
+			}
+			if err := tx.Create(&reminder).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if !tagsChanged {
+		return nil
+	}
+	app.Tags = nil
+	if err := tx.Model(app).Association("Tags").Find(&app.Tags); err != nil {
+		return err
+	}
+	return PublishApplicationEvent(tx, models.EventApplicationUpdated, app)
+}
+
+// RunAutomationRule acts on every application the rule matches at now and
+// logs each execution, as part of tx. An execution that fails is logged
+// with its error and not retried.
+func RunAutomationRule(tx *gorm.DB, rule *models.AutomationRule, now time.Time) (int, error) {
+	matches, err := EvaluateAutomationRule(tx, rule, rule.CheckedUntil, now)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, match := range matches {
+		execution := models.AutomationExecution{
+			UserID:        rule.UserID,
+			RuleID:        rule.ID,
+			ApplicationID: match.ApplicationID,
+			TriggerKey:    match.TriggerKey,
+			Result:        models.ExecutionSucceeded,
+			Actions:       match.Actions,
+		}
+		err := tx.Transaction(func(tx *gorm.DB) error {
+			if err := runAutomationActions(tx, rule, match.app, now); err != nil {
+				return err
+			}
+			return tx.Create(&execution).Error
+		})
+		if err != nil {
+			execution.ID = 0
+			execution.Result = models.ExecutionFailed
+			execution.Error = err.Error()
+			if err := tx.Create(&execution).Error; err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	updates := map[string]interface{}{"last_run_at": now}
+	if checked := now.AddDate(0, 0, -rule.DelayDays); rule.Trigger != models.TriggerSchedule && checked.After(rule.CheckedUntil) {
+		updates["checked_until"] = checked
+	}
+	if err := tx.Model(rule).Updates(updates).Error; err != nil {
+		return 0, err
+	}
+	return len(matches), nil
+}
+
+// StartAutomationWorker runs every enabled automation rule every
+// AUTOMATION_INTERVAL
+func StartAutomationWorker() {
+	interval := config.GetEnvDuration("AUTOMATION_INTERVAL", 15*time.Minute)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// A rule that fails is retried on the next pass, without holding
+			// up the rules after it
+			start := time.Now()
+			failed := []uint{}
+			for {
+				ruleID, err := runNextAutomationRule(start, failed)
+				if err != nil && ruleID != 0 {
+					log.Printf("Automation: failed to run rule %d: %v", ruleID, err)
+					failed = append(failed, ruleID)
+					continue
+				}
+				if err != nil {
+					log.Printf("Automation: failed to load rules: %v", err)
+				}
+				if ruleID == 0 {
+					break
+				}
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// runNextAutomationRule runs one enabled rule that hasn't run since start,
+// other than the skipped ones, and returns its ID, or 0 if there was none
+func runNextAutomationRule(start time.Time, skip []uint) (uint, error) {
+	var ruleID uint
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("enabled = ? AND (last_run_at IS NULL OR last_run_at < ?)", true, start)
+		if len(skip) > 0 {
+			query = query.Where("id NOT IN ?", skip)
+		}
+		var rule models.AutomationRule
+		err := query.Order("id ASC").First(&rule).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ruleID = rule.ID
+
+		count, err := RunAutomationRule(tx, &rule, time.Now())
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Printf("Automation: rule %d acted on %d applications", rule.ID, count)
+		}
+		return nil
+	})
+	return ruleID, err
+}
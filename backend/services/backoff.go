@@ -0,0 +1,20 @@
+package services
+
+import (
+	"math/rand"
+	"time"
+)
+
+// backoffDelay returns the wait before the next attempt after the given number
+// of failed attempts: base doubled per attempt, capped at max, plus up to 20%
+// jitter so failed deliveries don't all retry at the same instant.
+func backoffDelay(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
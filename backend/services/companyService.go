@@ -0,0 +1,308 @@
+package services
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+)
+
+// Legal suffixes ignored when comparing company names
+var companySuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "llc": true, "llp": true, "lp": true,
+	"ltd": true, "limited": true, "corp": true, "corporation": true,
+	"co": true, "company": true, "plc": true, "gmbh": true, "ag": true,
+	"sa": true, "bv": true,
+}
+
+// companySuggestionThreshold is the lowest score worth suggesting
+const companySuggestionThreshold = 0.75
+
+// maxCompanyName is the size of a company's name column
+const maxCompanyName = 100
+
+// NormalizeCompanyName reduces a company name to the form used to compare
+// names: lowercase, without punctuation and without trailing legal
+// suffixes, so "Google, LLC" becomes "google"
+func NormalizeCompanyName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+	for len(words) > 1 && companySuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// CompanyNames returns the company's name followed by its aliases
+func CompanyNames(company *models.Company) []string {
+	return append([]string{company.Name}, company.Aliases...)
+}
+
+// companyMatches reports whether the company's name or one of its aliases
+// normalizes to key
+func companyMatches(company *models.Company, key string) bool {
+	for _, name := range CompanyNames(company) {
+		if NormalizeCompanyName(name) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// CompanyHasName reports whether name resolves to the company
+func CompanyHasName(company *models.Company, name string) bool {
+	key := NormalizeCompanyName(name)
+	return key != "" && companyMatches(company, key)
+}
+
+// FindCompany returns the user's company whose name or an alias matches
+// name, or nil if there is none
+func FindCompany(db *gorm.DB, userID uint, name string) (*models.Company, error) {
+	key := NormalizeCompanyName(name)
+	if key == "" {
+		return nil, nil
+	}
+
+	var companies []models.Company
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&companies).Error; err != nil {
+		return nil, err
+	}
+	for i := range companies {
+		if companyMatches(&companies[i], key) {
+			return &companies[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// LinkCompany points the application at the user's company matching its
+// company name and switches it to the canonical name. A company is created
+// if none matches; created reports whether that happened. Application
+// company names aren't limited in length, so longer ones are cut to fit.
+func LinkCompany(tx *gorm.DB, app *models.Application) (company *models.Company, created bool, err error) {
+	app.CompanyID = nil
+	name := strings.TrimSpace(truncate(strings.TrimSpace(app.Company), maxCompanyName))
+	if NormalizeCompanyName(name) == "" {
+		return nil, false, nil
+	}
+
+	company, err = FindCompany(tx, app.UserID, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if company == nil {
+		company = &models.Company{
+			UserID:         app.UserID,
+			Name:           name,
+			NormalizedName: NormalizeCompanyName(name),
+			Aliases:        []string{},
+		}
+		if err := tx.Create(company).Error; err != nil {
+			return nil, false, err
+		}
+		created = true
+	}
+
+	app.CompanyID = &company.ID
+	app.Company = company.Name
+	return company, created, nil
+}
+
+// SuggestCompanies returns the user's companies with a name or alias
+// similar to name, best match first. excludeID leaves out one company,
+// usually the one that was just created from name.
+func SuggestCompanies(db *gorm.DB, userID uint, name string, excludeID uint, limit int) ([]models.CompanySuggestion, error) {
+	key := NormalizeCompanyName(name)
+	suggestions := []models.CompanySuggestion{}
+	if key == "" {
+		return suggestions, nil
+	}
+
+	var companies []models.Company
+	if err := db.Where("user_id = ? AND id <> ?", userID, excludeID).Find(&companies).Error; err != nil {
+		return nil, err
+	}
+
+	for _, company := range companies {
+		best := models.CompanySuggestion{Company: company}
+		for _, candidate := range CompanyNames(&company) {
+			if score := nameSimilarity(key, NormalizeCompanyName(candidate)); score > best.Score {
+				best.Score = score
+				best.MatchedName = candidate
+			}
+		}
+		if best.Score >= companySuggestionThreshold {
+			best.Score = math.Round(best.Score*100) / 100
+			suggestions = append(suggestions, best)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// nameSimilarity scores two normalized names from 0 to 1. A name that is
+// the other plus extra words ("meta" and "meta platforms") scores 0.9.
+func nameSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	if strings.HasPrefix(a, b+" ") || strings.HasPrefix(b, a+" ") {
+		return 0.9
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// AddCompanyAlias adds alias to the company unless it already resolves to it
+func AddCompanyAlias(company *models.Company, alias string) {
+	alias = strings.TrimSpace(alias)
+	if key := NormalizeCompanyName(alias); key == "" || companyMatches(company, key) {
+		return
+	}
+	company.Aliases = append(company.Aliases, alias)
+}
+
+// RenameCompanyApplications updates the company name stored on the
+// company's applications, including those in the trash
+func RenameCompanyApplications(tx *gorm.DB, company *models.Company) error {
+	return tx.Unscoped().Model(&models.Application{}).
+		Where("company_id = ?", company.ID).
+		Update("company", company.Name).Error
+}
+
+// MergeCompanies folds sources into target: their applications move to
+// target, their names become aliases of target and details missing on
+// target are copied over. It returns the number of applications moved.
+func MergeCompanies(tx *gorm.DB, target *models.Company, sources []models.Company) (int, error) {
+	sourceIDs := make([]uint, 0, len(sources))
+	for i := range sources {
+		source := &sources[i]
+		sourceIDs = append(sourceIDs, source.ID)
+		for _, name := range CompanyNames(source) {
+			AddCompanyAlias(target, name)
+		}
+		if target.Website == "" {
+			target.Website = source.Website
+		}
+		if target.Industry == "" {
+			target.Industry = source.Industry
+		}
+		if target.Headquarters == "" {
+			target.Headquarters = source.Headquarters
+		}
+	}
+
+	var apps []models.Application
+	if err := tx.Unscoped().Where("company_id IN ?", sourceIDs).Find(&apps).Error; err != nil {
+		return 0, err
+	}
+	for i := range apps {
+		app := &apps[i]
+		app.CompanyID = &target.ID
+		app.Company = target.Name
+		if err := tx.Unscoped().Model(app).Updates(map[string]interface{}{
+			"company_id": target.ID,
+			"company":    target.Name,
+		}).Error; err != nil {
+			return 0, err
+		}
+		if app.DeletedAt.Valid {
+			continue
+		}
+		if err := PublishApplicationEvent(tx, models.EventApplicationUpdated, app); err != nil {
+			return 0, err
+		}
+	}
+
+	// Sources go first so their normalized names are free if one of them
+	// becomes the target's name later
+	if err := tx.Delete(&models.Company{}, sourceIDs).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Save(target).Error; err != nil {
+		return 0, err
+	}
+	return len(apps), nil
+}
+
+// BackfillCompanies links applications saved before companies existed. It
+// runs at startup and only touches applications without a company.
+func BackfillCompanies() {
+	var apps []models.Application
+	if err := config.DB.Unscoped().
+		Where("company_id IS NULL AND company <> ''").
+		Order("user_id ASC, id ASC").
+		Find(&apps).Error; err != nil {
+		log.Printf("Companies: failed to load applications to backfill: %v", err)
+		return
+	}
+
+	linked := 0
+	for i := range apps {
+		app := &apps[i]
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			if _, _, err := LinkCompany(tx, app); err != nil {
+				return err
+			}
+			if app.CompanyID == nil {
+				return nil
+			}
+			return tx.Unscoped().Model(app).Updates(map[string]interface{}{
+				"company_id": app.CompanyID,
+				"company":    app.Company,
+			}).Error
+		})
+		if err != nil {
+			log.Printf("Companies: failed to backfill application %d: %v", app.ID, err)
+			continue
+		}
+		linked++
+	}
+	if linked > 0 {
+		log.Printf("Companies: linked %d applications to companies", linked)
+	}
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+)
+
+const digestUnsubscribePurpose = "digest-unsubscribe"
+
+// Digest is the content of one weekly progress email
+type Digest struct {
+	Username       string
+	Location       *time.Location
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	Added          []models.Application
+	StatusChanges  []DigestStatusChange
+	Interviews     []models.Interview
+	Reminders      []models.Reminder
+	Stale          []models.Application
+	StaleDays      int
+	AppURL         string
+	UnsubscribeURL string
+}
+
+type DigestStatusChange struct {
+	Company  string
+	Position string
+	From     models.ApplicationStatus
+	To       models.ApplicationStatus
+	At       time.Time
+}
+
+func (d *Digest) isEmpty() bool {
+	return len(d.Added) == 0 && len(d.StatusChanges) == 0 && len(d.Interviews) == 0 &&
+		len(d.Reminders) == 0 && len(d.Stale) == 0
+}
+
+// UserLocation returns the user's configured time zone, falling back to UTC
+func UserLocation(user *models.User) *time.Location {
+	if loc, err := time.LoadLocation(user.Timezone); err == nil && user.Timezone != "" {
+		return loc
+	}
+	return time.UTC
+}
+
+// DigestUnsubscribeURL returns the signed one-click unsubscribe link for a user
+func DigestUnsubscribeURL(userID uint) string {
+	id := strconv.FormatUint(uint64(userID), 10)
+	return fmt.Sprintf("%s/digest/unsubscribe?user=%s&sig=%s",
+		config.GetBackendURL(), id, SignValue(digestUnsubscribePurpose, id))
+}
+
+// VerifyDigestUnsubscribe checks the signature of an unsubscribe link
+func VerifyDigestUnsubscribe(userID, signature string) bool {
+	return VerifySignedValue(digestUnsubscribePurpose, userID, signature)
+}
+
+// BuildDigest collects the digest content for the week ending at now
+func BuildDigest(db *gorm.DB, user *models.User, now time.Time) (*Digest, error) {
+	since := now.AddDate(0, 0, -7)
+	staleDays := config.GetEnvInt("DIGEST_STALE_DAYS", 14)
+
+	digest := &Digest{
+		Username:       user.Username,
+		Location:       UserLocation(user),
+		PeriodStart:    since,
+		PeriodEnd:      now,
+		StaleDays:      staleDays,
+		AppURL:         os.Getenv("FRONTEND_URL") + "/applications",
+		UnsubscribeURL: DigestUnsubscribeURL(user.ID),
+	}
+
+	if err := db.Where("user_id = ? AND created_at >= ?", user.ID, since).
+		Order("created_at ASC").Find(&digest.Added).Error; err != nil {
+		return nil, err
+	}
+
+	var changes []models.ApplicationStatusChange
+	if err := db.Where("user_id = ? AND created_at >= ?", user.ID, since).
+		Order("created_at ASC").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 {
+		appIDs := make([]uint, 0, len(changes))
+		for _, change := range changes {
+			appIDs = append(appIDs, change.ApplicationID)
+		}
+		var apps []models.Application
+		if err := db.Where("id IN ?", appIDs).Find(&apps).Error; err != nil {
+			return nil, err
+		}
+		appsByID := make(map[uint]models.Application, len(apps))
+		for _, app := range apps {
+			appsByID[app.ID] = app
+		}
+		for _, change := range changes {
+			app, ok := appsByID[change.ApplicationID]
+			if !ok {
+				continue
+			}
+			digest.StatusChanges = append(digest.StatusChanges, DigestStatusChange{
+				Company:  app.Company,
+				Position: app.Position,
+				From:     change.FromStatus,
+				To:       change.ToStatus,
+				At:       change.CreatedAt,
+			})
+		}
+	}
+
+	nextWeek := now.AddDate(0, 0, 7)
+	if err := db.Preload("Application").Scopes(models.WithoutTrashedApplications).
+		Where("user_id = ? AND scheduled_at BETWEEN ? AND ?", user.ID, now, nextWeek).
+		Order("scheduled_at ASC").Find(&digest.Interviews).Error; err != nil {
+		return nil, err
+	}
+
+	// Overdue reminders are included too, they still need doing
+	if err := db.Preload("Application").Scopes(models.WithoutTrashedApplications).
+		Where("user_id = ? AND completed_at IS NULL AND due_at <= ?", user.ID, nextWeek).
+		Order("due_at ASC").Find(&digest.Reminders).Error; err != nil {
+		return nil, err
+	}
+
+	staleBefore := now.AddDate(0, 0, -staleDays)
+	if err := db.Where("user_id = ? AND archived = ? AND status IN ? AND COALESCE(updated_at, applied_date) < ?",
+		user.ID, false, []models.ApplicationStatus{models.StatusApplied, models.StatusOAReceived, models.StatusInterviewing}, staleBefore).
+		Order("COALESCE(updated_at, applied_date) ASC").Find(&digest.Stale).Error; err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// RenderDigest renders the digest email body
+func RenderDigest(digest *Digest) (string, error) {
+	return renderTemplate("weekly_digest.html", digest)
+}
+
+// StartDigestWorker periodically sends the weekly digest to opted-in users
+// once it is the configured weekday and hour in their own time zone
+func StartDigestWorker() {
+	interval := config.GetEnvDuration("DIGEST_CHECK_INTERVAL", 15*time.Minute)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			sendDueDigests(time.Now())
+			<-ticker.C
+		}
+	}()
+}
+
+func sendDueDigests(now time.Time) {
+	weekday := digestWeekday()
+	hour := config.GetEnvInt("DIGEST_HOUR", 8)
+
+	var users []models.User
+	if err := config.DB.Where("weekly_digest = ? AND is_verified = ? AND deletion_scheduled_at IS NULL", true, true).Find(&users).Error; err != nil {
+		log.Printf("Digest: failed to load users: %v", err)
+		return
+	}
+
+	for i := range users {
+		user := &users[i]
+		local := now.In(UserLocation(user))
+		if local.Weekday() != weekday || local.Hour() < hour {
+			continue
+		}
+		if user.LastDigestSentAt != nil && now.Sub(*user.LastDigestSentAt) < 6*24*time.Hour {
+			continue
+		}
+
+		if err := sendDigest(user, now); err != nil {
+			log.Printf("Digest: failed to send digest to user %d: %v", user.ID, err)
+		}
+	}
+}
+
+func sendDigest(user *models.User, now time.Time) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		// Claim this week's digest first so only one replica sends it
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND (last_digest_sent_at IS NULL OR last_digest_sent_at < ?)", user.ID, now.Add(-6*24*time.Hour)).
+			UpdateColumn("last_digest_sent_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		digest, err := BuildDigest(tx, user, now)
+		if err != nil {
+			return err
+		}
+		if digest.isEmpty() {
+			return nil
+		}
+
+		body, err := RenderDigest(digest)
+		if err != nil {
+			return err
+		}
+
+		headers := map[string]string{
+			"List-Unsubscribe":      "<" + digest.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+		return EnqueueEmailWithHeaders(tx, user.Email, "Your weekly Internship Hub digest", body, headers)
+	})
+}
+
+func digestWeekday() time.Weekday {
+	name := strings.ToLower(os.Getenv("DIGEST_WEEKDAY"))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == name {
+			return day
+		}
+	}
+	return time.Monday
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// maxEmailBody caps how much of a message body is read
+const maxEmailBody = 1 << 20
+
+// ParsedEmail is the part of an RFC 822 message ingestion looks at
+type ParsedEmail struct {
+	MessageID   string
+	FromAddress string
+	FromName    string
+	Recipients  []string // To, Cc, Delivered-To and X-Original-To addresses
+	Subject     string
+	Date        time.Time
+	Body        string // Plain text, converted from HTML when there is no text part
+	ForwardedBy string // Who forwarded the message by hand, its From is then the original sender
+}
+
+var (
+	forwardSubjectPattern = regexp.MustCompile(`(?i)^\s*(fwd?|fw)\s*:\s*`)
+	forwardedFromPattern  = regexp.MustCompile(`(?im)^[>\s]*from:\s*(.+)$`)
+)
+
+// ParseEmail reads a raw RFC 822 message. Messages forwarded by hand
+// ("Fwd: ...") are unwrapped, so the original sender is the one matched.
+func ParseEmail(r io.Reader) (*ParsedEmail, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+	email := &ParsedEmail{
+		MessageID: strings.Trim(strings.TrimSpace(msg.Header.Get("Message-Id")), "<>"),
+		Subject:   msg.Header.Get("Subject"),
+	}
+	if subject, err := decoder.DecodeHeader(email.Subject); err == nil {
+		email.Subject = subject
+	}
+	email.Subject = strings.Join(strings.Fields(email.Subject), " ")
+
+	parser := &mail.AddressParser{WordDecoder: decoder}
+	if from, err := parser.Parse(msg.Header.Get("From")); err == nil {
+		email.FromAddress = strings.ToLower(from.Address)
+		email.FromName = from.Name
+	}
+	for _, key := range []string{"To", "Cc", "Delivered-To", "X-Original-To"} {
+		for _, value := range msg.Header[key] {
+			addresses, err := parser.ParseList(value)
+			if err != nil {
+				continue
+			}
+			for _, address := range addresses {
+				email.Recipients = append(email.Recipients, strings.ToLower(address.Address))
+			}
+		}
+	}
+	if email.Date, err = msg.Header.Date(); err != nil {
+		email.Date = time.Now()
+	}
+
+	plain, html, err := readEmailPart(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	email.Body = strings.TrimSpace(plain)
+	if email.Body == "" {
+		email.Body = HTMLToText(html)
+	}
+
+	if email.MessageID == "" {
+		// Without a Message-ID, the same message must still be recognized
+		sum := sha256.Sum256([]byte(email.FromAddress + "\x00" + email.Subject + "\x00" + msg.Header.Get("Date") + "\x00" + email.Body))
+		email.MessageID = "sha256:" + hex.EncodeToString(sum[:16])
+	}
+
+	email.unwrapForward()
+	return email, nil
+}
+
+// readEmailPart returns the first text/plain and text/html content of a
+// part, walking into multipart parts and skipping attachments
+func readEmailPart(contentType, encoding string, body io.Reader) (plain, html string, err error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return plain, html, err
+			}
+			if strings.HasPrefix(strings.ToLower(part.Header.Get("Content-Disposition")), "attachment") {
+				continue
+			}
+			p, h, err := readEmailPart(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return plain, html, err
+			}
+			if plain == "" {
+				plain = p
+			}
+			if html == "" {
+				html = h
+			}
+		}
+		return plain, html, nil
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	// Unknown charsets are read as they are
+	if decoded, err := charset.NewReaderLabel(params["charset"], body); err == nil && params["charset"] != "" {
+		body = decoded
+	}
+
+	content, err := io.ReadAll(io.LimitReader(body, maxEmailBody))
+	if err != nil {
+		return "", "", err
+	}
+	if mediaType == "text/html" {
+		return "", string(content), nil
+	}
+	return string(content), "", nil
+}
+
+// unwrapForward replaces the sender and subject of a message forwarded by
+// hand with the ones of the forwarded message
+func (e *ParsedEmail) unwrapForward() {
+	if !forwardSubjectPattern.MatchString(e.Subject) {
+		return
+	}
+	e.Subject = forwardSubjectPattern.ReplaceAllString(e.Subject, "")
+
+	m := forwardedFromPattern.FindStringSubmatch(e.Body)
+	if m == nil {
+		return
+	}
+	if from, err := mail.ParseAddress(strings.TrimSpace(m[1])); err == nil {
+		e.ForwardedBy = e.FromAddress
+		e.FromAddress = strings.ToLower(from.Address)
+		e.FromName = from.Name
+	}
+}
+
+// EmailDomain returns the domain of an email address
+func EmailDomain(address string) string {
+	_, domain, _ := strings.Cut(strings.ToLower(address), "@")
+	return domain
+}
@@ -4,17 +4,40 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-	"gopkg.in/gomail.v2"
+	"html"
 	"os"
 	"strconv"
+	"time"
+
+	"gopkg.in/gomail.v2"
+	"gorm.io/gorm"
 )
 
-func SendVerificationEmail(email, token string) error {
+// SendEmail delivers a single HTML email over SMTP. Callers that are part of
+// a request should use EnqueueEmail instead so delivery is retried.
+func SendEmail(recipient, subject, body string, headers map[string]string) error {
 	m := gomail.NewMessage()
 	m.SetHeader("From", os.Getenv("SMTP_USER"))
-	m.SetHeader("To", email)
-	m.SetHeader("Subject", "Verify Your Email - Internship Hub")
+	m.SetHeader("To", recipient)
+	m.SetHeader("Subject", subject)
+	for name, value := range headers {
+		m.SetHeader(name, value)
+	}
+	m.SetBody("text/html", body)
+
+	port, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	d := gomail.NewDialer(
+		os.Getenv("SMTP_HOST"),
+		port,
+		os.Getenv("SMTP_USER"),
+		os.Getenv("SMTP_PASSWORD"),
+	)
 
+	return d.DialAndSend(m)
+}
+
+// EnqueueVerificationEmail queues the signup verification email as part of tx
+func EnqueueVerificationEmail(tx *gorm.DB, email, token string) error {
 	verifyURL := fmt.Sprintf("%s/verify-email?token=%s",
 		os.Getenv("FRONTEND_URL"), token)
 
@@ -27,17 +50,43 @@ func SendVerificationEmail(email, token string) error {
         <p>If you didn't create an account, please ignore this email.</p>
     `, verifyURL, verifyURL)
 
-	m.SetBody("text/html", body)
+	return EnqueueEmail(tx, email, "Verify Your Email - Internship Hub", body)
+}
 
-	port, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
-	d := gomail.NewDialer(
-		os.Getenv("SMTP_HOST"),
-		port,
-		os.Getenv("SMTP_USER"),
-		os.Getenv("SMTP_PASSWORD"),
-	)
+// EnqueueEmailChangeEmails asks the new address to confirm an email change
+// and lets the current address know about it
+func EnqueueEmailChangeEmails(tx *gorm.DB, currentEmail, newEmail, token string) error {
+	verifyURL := fmt.Sprintf("%s/verify-email?token=%s",
+		os.Getenv("FRONTEND_URL"), token)
 
-	return d.DialAndSend(m)
+	body := fmt.Sprintf(`
+        <h2>Confirm your new email address</h2>
+        <p>Please click the link below to use this address for your Internship Hub account:</p>
+        <a href="%s" style="background-color: #4CAF50; color: white; padding: 10px 20px; text-decoration: none; border-radius: 5px;">Confirm Email</a>
+        <p>Or copy and paste this link in your browser: %s</p>
+        <p>This link expires in 24 hours. Your email address won't change until you confirm it.</p>
+    `, verifyURL, verifyURL)
+	if err := EnqueueEmail(tx, newEmail, "Confirm Your New Email - Internship Hub", body); err != nil {
+		return err
+	}
+
+	notice := fmt.Sprintf(`
+        <h2>Email change requested</h2>
+        <p>Someone asked to change the email address of your Internship Hub account to %s.</p>
+        <p>If this wasn't you, change your password right away. The change only happens once the new address is confirmed.</p>
+    `, html.EscapeString(newEmail))
+	return EnqueueEmail(tx, currentEmail, "Email Change Requested - Internship Hub", notice)
+}
+
+// EnqueueAccountDeletionEmail tells the user when their account will be deleted
+func EnqueueAccountDeletionEmail(tx *gorm.DB, email string, deleteAt time.Time) error {
+	body := fmt.Sprintf(`
+        <h2>Your account is scheduled for deletion</h2>
+        <p>Your Internship Hub account and all of its applications and resumes will be permanently deleted on %s.</p>
+        <p>Changed your mind? Log in before then and restore your account from the settings page.</p>
+    `, deleteAt.UTC().Format("January 2, 2006 at 15:04 MST"))
+
+	return EnqueueEmail(tx, email, "Account Deletion Scheduled - Internship Hub", body)
 }
 
 func GenerateVerificationToken() string {
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// applicationEventsChannel is the Postgres NOTIFY channel used to fan events
+// out to every backend replica
+const applicationEventsChannel = "application_events"
+
+// eventBroker hands application events to the event streams open on this
+// replica, keyed by user
+type eventBroker struct {
+	mu          sync.Mutex
+	subscribers map[uint]map[chan models.ApplicationEvent]struct{}
+}
+
+var broker = &eventBroker{
+	subscribers: make(map[uint]map[chan models.ApplicationEvent]struct{}),
+}
+
+// SubscribeApplicationEvents registers an event stream for the user. The
+// returned channel is closed if the subscriber falls too far behind, in which
+// case the client should reconnect and resume with Last-Event-ID.
+func SubscribeApplicationEvents(userID uint) (<-chan models.ApplicationEvent, func()) {
+	ch := make(chan models.ApplicationEvent, 32)
+
+	broker.mu.Lock()
+	if broker.subscribers[userID] == nil {
+		broker.subscribers[userID] = make(map[chan models.ApplicationEvent]struct{})
+	}
+	broker.subscribers[userID][ch] = struct{}{}
+	broker.mu.Unlock()
+
+	unsubscribe := func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		if _, ok := broker.subscribers[userID][ch]; ok {
+			delete(broker.subscribers[userID], ch)
+			close(ch)
+		}
+		if len(broker.subscribers[userID]) == 0 {
+			delete(broker.subscribers, userID)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+func (b *eventBroker) dispatch(event models.ApplicationEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+			// Slow client, drop it rather than block everyone else
+			delete(b.subscribers[event.UserID], ch)
+			close(ch)
+		}
+	}
+}
+
+// PublishApplicationEvent records an application change as part of tx and
+// queues it for the user's webhooks. The NOTIFY is only delivered by Postgres
+// once tx commits, so streams never see changes that were rolled back.
+func PublishApplicationEvent(tx *gorm.DB, eventType string, app *models.Application) error {
+	var data interface{} = app
+	if eventType == models.EventApplicationDeleted {
+		data = map[string]uint{"id": app.ID}
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if err := EnqueueWebhookEvent(tx, app.UserID, eventType, data); err != nil {
+		return err
+	}
+
+	event := models.ApplicationEvent{
+		UserID:        app.UserID,
+		Type:          eventType,
+		ApplicationID: app.ID,
+		Payload:       string(payload),
+	}
+	if err := tx.Create(&event).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec("SELECT pg_notify(?, ?)", applicationEventsChannel, strconv.FormatUint(uint64(event.ID), 10)).Error
+}
+
+// ApplicationEventsSince returns the user's events after lastEventID, oldest first
+func ApplicationEventsSince(userID uint, lastEventID uint) ([]models.ApplicationEvent, error) {
+	var events []models.ApplicationEvent
+	err := config.DB.Where("user_id = ? AND id > ?", userID, lastEventID).
+		Order("id ASC").Limit(500).Find(&events).Error
+	return events, err
+}
+
+// StartEventListener listens for application events from every replica and
+// dispatches them to the event streams open on this one. It also prunes
+// events older than EVENT_RETENTION, after which streams can't resume.
+func StartEventListener() {
+	go func() {
+		for {
+			if err := listenForEvents(context.Background()); err != nil {
+				log.Printf("Events: listener stopped: %v", err)
+			}
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for {
+			retention := config.GetEnvDuration("EVENT_RETENTION", 24*time.Hour)
+			if err := config.DB.Where("created_at < ?", time.Now().Add(-retention)).Delete(&models.ApplicationEvent{}).Error; err != nil {
+				log.Printf("Events: failed to prune old events: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+func listenForEvents(ctx context.Context) error {
+	pgConfig, err := pgconn.ParseConfig(config.DSN())
+	if err != nil {
+		return err
+	}
+
+	eventIDs := make(chan string, 256)
+	pgConfig.OnNotification = func(_ *pgconn.PgConn, n *pgconn.Notification) {
+		eventIDs <- n.Payload
+	}
+
+	conn, err := pgconn.ConnectConfig(ctx, pgConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	if _, err := conn.Exec(ctx, "LISTEN "+applicationEventsChannel).ReadAll(); err != nil {
+		return err
+	}
+
+	go func() {
+		for payload := range eventIDs {
+			id, err := strconv.ParseUint(payload, 10, 64)
+			if err != nil {
+				continue
+			}
+			var event models.ApplicationEvent
+			if err := config.DB.First(&event, id).Error; err != nil {
+				log.Printf("Events: failed to load event %d: %v", id, err)
+				continue
+			}
+			broker.dispatch(event)
+		}
+	}()
+	defer close(eventIDs)
+
+	for {
+		if err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Export archives contain manifest.json, data.json and the resume files
+// under resumes/. Bump ExportSchemaVersion when data.json changes shape.
+// Version 2 added tags, version 3 companies, version 4 offers, version 5
+// the wishlist.
+const (
+	ExportFormat        = "internship-hub-export"
+	ExportSchemaVersion = 5
+
+	exportDownloadPurpose = "export-download"
+)
+
+// ExportManifest describes an export archive
+type ExportManifest struct {
+	Format        string         `json:"format"`
+	SchemaVersion int            `json:"schema_version"`
+	ExportedAt    time.Time      `json:"exported_at"`
+	UserID        uint           `json:"user_id"`
+	Counts        map[string]int `json:"counts"`
+	ResumeFiles   []string       `json:"resume_files"`
+	MissingFiles  []string       `json:"missing_files,omitempty"` // Referenced but not found on the server
+}
+
+// ExportApplication is an application as written to data.json. ResumeFile
+// is the path of its resume inside the archive.
+type ExportApplication struct {
+	models.Application
+	User       *struct{} `json:"user,omitempty"` // Hides the embedded User, the profile is exported once
+	ResumeFile string    `json:"resume_file,omitempty"`
+}
+
+// ExportData is the content of data.json. Notes are part of each application.
+// There are no contacts, the app doesn't store any.
+type ExportData struct {
+	Profile              models.User                      `json:"profile"`
+	Applications         []ExportApplication              `json:"applications"`
+	StatusHistory        []models.ApplicationStatusChange `json:"status_history"`
+	Interviews           []models.Interview               `json:"interviews"`
+	Reminders            []models.Reminder                `json:"reminders"`
+	Offers               []models.Offer                   `json:"offers"`
+	Tags                 []models.Tag                     `json:"tags"`
+	Companies            []models.Company                 `json:"companies"`
+	Wishlist             []models.WishlistItem            `json:"wishlist"`
+	NotificationSettings models.NotificationSettings      `json:"notification_settings"`
+}
+
+// ExportsDir is where background exports are stored until they expire
+func ExportsDir() string {
+	if dir := os.Getenv("EXPORTS_DIR"); dir != "" {
+		return dir
+	}
+	return "./exports"
+}
+
+// WriteUserExport writes the user's export archive to w
+func WriteUserExport(db *gorm.DB, w io.Writer, userID uint) error {
+	data, err := loadExportData(db, userID)
+	if err != nil {
+		return err
+	}
+
+	manifest := ExportManifest{
+		Format:        ExportFormat,
+		SchemaVersion: ExportSchemaVersion,
+		ExportedAt:    time.Now().UTC(),
+		UserID:        userID,
+		Counts: map[string]int{
+			"applications":   len(data.Applications),
+			"status_history": len(data.StatusHistory),
+			"interviews":     len(data.Interviews),
+			"reminders":      len(data.Reminders),
+			"offers":         len(data.Offers),
+			"tags":           len(data.Tags),
+			"companies":      len(data.Companies),
+			"wishlist":       len(data.Wishlist),
+		},
+		ResumeFiles: []string{},
+	}
+
+	archive := zip.NewWriter(w)
+
+	// Copy resumes first so data.json only points at files that made it in
+	for i := range data.Applications {
+		app := &data.Applications[i]
+		if app.ResumeURL == "" {
+			continue
+		}
+
+		name := "resumes/" + strconv.FormatUint(uint64(app.ID), 10) + "-" + path.Base(app.ResumeURL)
+		if err := addFileToArchive(archive, name, "."+app.ResumeURL); err != nil {
+			if os.IsNotExist(err) {
+				manifest.MissingFiles = append(manifest.MissingFiles, app.ResumeURL)
+				continue
+			}
+			return err
+		}
+		app.ResumeFile = name
+		manifest.ResumeFiles = append(manifest.ResumeFiles, name)
+	}
+
+	if err := addJSONToArchive(archive, "data.json", data); err != nil {
+		return err
+	}
+	if err := addJSONToArchive(archive, "manifest.json", manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func loadExportData(db *gorm.DB, userID uint) (*ExportData, error) {
+	data := &ExportData{}
+	if err := db.First(&data.Profile, userID).Error; err != nil {
+		return nil, err
+	}
+
+	var apps []models.Application
+	if err := db.Preload("Tags").Where("user_id = ?", userID).Order("id ASC").Find(&apps).Error; err != nil {
+		return nil, err
+	}
+	data.Applications = make([]ExportApplication, len(apps))
+	for i := range apps {
+		data.Applications[i] = ExportApplication{Application: apps[i]}
+	}
+
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&data.StatusHistory).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&data.Interviews).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&data.Reminders).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&data.Offers).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("name ASC").Find(&data.Tags).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("name ASC").Find(&data.Companies).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&data.Wishlist).Error; err != nil {
+		return nil, err
+	}
+
+	settings, err := GetNotificationSettings(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	data.NotificationSettings = settings
+	return data, nil
+}
+
+func addFileToArchive(archive *zip.Writer, name, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+func addJSONToArchive(archive *zip.Writer, name string, v interface{}) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// ExportDownloadURL returns the emailed link to a finished export. It works
+// without logging in, so it is signed and dies with the export.
+func ExportDownloadURL(exportID uint) string {
+	id := strconv.FormatUint(uint64(exportID), 10)
+	return fmt.Sprintf("%s/exports/download?export=%s&sig=%s",
+		config.GetBackendURL(), id, SignValue(exportDownloadPurpose, id))
+}
+
+// VerifyExportDownload checks the signature of an export download link
+func VerifyExportDownload(exportID, signature string) bool {
+	return VerifySignedValue(exportDownloadPurpose, exportID, signature)
+}
+
+// StartExportWorker builds queued exports and deletes expired ones
+func StartExportWorker() {
+	interval := config.GetEnvDuration("EXPORT_POLL_INTERVAL", 30*time.Second)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for {
+				processed, err := buildNextExport()
+				if err != nil {
+					log.Printf("Exports: failed to build export: %v", err)
+				}
+				if err != nil || !processed {
+					break
+				}
+			}
+			pruneExpiredExports()
+			<-ticker.C
+		}
+	}()
+}
+
+// buildNextExport builds the oldest pending export, if any. The row stays
+// locked while building so replicas never build the same export twice.
+func buildNextExport() (bool, error) {
+	processed := false
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var export models.DataExport
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", models.ExportPending).
+			Order("created_at ASC").
+			Limit(1).
+			Find(&export)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		processed = true
+
+		var user models.User
+		if err := tx.First(&user, export.UserID).Error; err != nil {
+			return tx.Delete(&export).Error
+		}
+
+		if err := writeExportFile(tx, &export); err != nil {
+			log.Printf("Exports: export %d failed: %v", export.ID, err)
+			export.Status = models.ExportFailed
+			export.Error = err.Error()
+			return tx.Save(&export).Error
+		}
+
+		if err := tx.Save(&export).Error; err != nil {
+			os.Remove(export.FilePath)
+			return err
+		}
+
+		body := fmt.Sprintf(`
+        <h2>Your data export is ready</h2>
+        <p>Download your Internship Hub data here:</p>
+        <a href="%s" style="background-color: #4CAF50; color: white; padding: 10px 20px; text-decoration: none; border-radius: 5px;">Download Export</a>
+        <p>The link works until %s, after which the archive is deleted.</p>
+    `, ExportDownloadURL(export.ID), export.ExpiresAt.UTC().Format("January 2, 2006 at 15:04 MST"))
+		return EnqueueEmail(tx, user.Email, "Your Data Export Is Ready - Internship Hub", body)
+	})
+
+	return processed, err
+}
+
+func writeExportFile(db *gorm.DB, export *models.DataExport) error {
+	if err := os.MkdirAll(ExportsDir(), 0o700); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 8)
+	rand.Read(suffix)
+	filePath := filepath.Join(ExportsDir(), fmt.Sprintf("export-%d-%s.zip", export.ID, hex.EncodeToString(suffix)))
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := WriteUserExport(db, file, export.UserID); err != nil {
+		file.Close()
+		os.Remove(filePath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(filePath)
+		return err
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(config.GetEnvDuration("EXPORT_RETENTION", 7*24*time.Hour))
+	export.Status = models.ExportReady
+	export.FilePath = filePath
+	export.Size = info.Size()
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	return nil
+}
+
+func pruneExpiredExports() {
+	var expired []models.DataExport
+	if err := config.DB.Where("expires_at < ?", time.Now()).Find(&expired).Error; err != nil {
+		log.Printf("Exports: failed to find expired exports: %v", err)
+		return
+	}
+
+	for _, export := range expired {
+		if export.FilePath != "" {
+			RemoveFiles([]string{export.FilePath})
+		}
+		config.DB.Delete(&export)
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+)
+
+// imapClient speaks the few IMAP4rev1 commands polling a mailbox needs
+type imapClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+// imapResponse is what the server sent before completing a command
+type imapResponse struct {
+	lines    []string // Untagged responses, without literals
+	literals [][]byte // Literals, in the order they were sent
+}
+
+// dialIMAP connects and logs in to the mailbox, then selects it
+func dialIMAP(cfg config.IMAPConfig) (*imapClient, error) {
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if cfg.TLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: cfg.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client := &imapClient{conn: conn, reader: bufio.NewReader(conn)}
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	greeting, err := client.readLine()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected greeting %q", greeting)
+	}
+
+	if !strings.HasPrefix(greeting, "* PREAUTH") {
+		if _, err := client.command("LOGIN " + imapQuote(cfg.Username) + " " + imapQuote(cfg.Password)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if _, err := client.command("SELECT " + imapQuote(cfg.Mailbox)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// imapQuote quotes a string argument
+func imapQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+func (c *imapClient) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// command sends a command and reads the responses up to its completion,
+// returning an error unless the server completed it with OK
+func (c *imapClient) command(cmd string) (*imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("A%03d", c.tag)
+	c.conn.SetDeadline(time.Now().Add(2 * time.Minute))
+	if _, err := io.WriteString(c.conn, tag+" "+cmd+"\r\n"); err != nil {
+		return nil, err
+	}
+
+	response := &imapResponse{}
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		// A line ending in {n} is followed by n bytes of literal data, then
+		// the rest of the line
+		for strings.HasSuffix(line, "}") {
+			open := strings.LastIndex(line, "{")
+			if open < 0 {
+				break
+			}
+			size, err := strconv.Atoi(line[open+1 : len(line)-1])
+			if err != nil {
+				break
+			}
+			literal := make([]byte, size)
+			if _, err := io.ReadFull(c.reader, literal); err != nil {
+				return nil, err
+			}
+			response.literals = append(response.literals, literal)
+			rest, err := c.readLine()
+			if err != nil {
+				return nil, err
+			}
+			line = line[:open] + rest
+		}
+
+		if strings.HasPrefix(line, tag+" ") {
+			status := strings.TrimPrefix(line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return nil, fmt.Errorf("%s failed: %s", strings.Fields(cmd)[0], status)
+			}
+			return response, nil
+		}
+		if strings.HasPrefix(line, "* ") {
+			response.lines = append(response.lines, line[2:])
+		}
+	}
+}
+
+// searchUnseen returns the UIDs of the messages not marked seen
+func (c *imapClient) searchUnseen() ([]uint32, error) {
+	response, err := c.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, err
+	}
+
+	var uids []uint32
+	for _, line := range response.lines {
+		if !strings.HasPrefix(line, "SEARCH") {
+			continue
+		}
+		for _, field := range strings.Fields(line)[1:] {
+			if uid, err := strconv.ParseUint(field, 10, 32); err == nil {
+				uids = append(uids, uint32(uid))
+			}
+		}
+	}
+	return uids, nil
+}
+
+// fetch returns the raw message, without marking it seen
+func (c *imapClient) fetch(uid uint32) ([]byte, error) {
+	response, err := c.command(fmt.Sprintf("UID FETCH %d BODY.PEEK[]", uid))
+	if err != nil {
+		return nil, err
+	}
+	if len(response.literals) == 0 {
+		return nil, errors.New("message not found")
+	}
+	return response.literals[0], nil
+}
+
+// markSeen flags a message as seen, so it isn't polled again
+func (c *imapClient) markSeen(uid uint32) error {
+	_, err := c.command(fmt.Sprintf(`UID STORE %d +FLAGS.SILENT (\Seen)`, uid))
+	return err
+}
+
+func (c *imapClient) logout() {
+	c.command("LOGOUT")
+	c.conn.Close()
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+	"strings"
+)
+
+// columnImporter reads formats that differ only in their column names.
+// columns maps each field to the headers it can be read from, compared
+// case-insensitively.
+type columnImporter struct {
+	format      string
+	description string
+	columns     map[string][]string
+	// signature are headers that must all be present for the format to be
+	// detected. Formats with a signature outrank ones without.
+	signature []string
+	// fuzzy matches headers containing a column name rather than equal to it
+	fuzzy bool
+	// wishlist sends every row to the wishlist, e.g. saved jobs
+	wishlist bool
+}
+
+var importers = []Importer{
+	&columnImporter{
+		format:      "linkedin_applications",
+		description: `LinkedIn data export "Job Applications" CSV`,
+		signature:   []string{"application date", "job url", "resume name"},
+		columns: map[string][]string{
+			ImportCompany:     {"company name"},
+			ImportPosition:    {"job title"},
+			ImportPostingURL:  {"job url"},
+			ImportAppliedDate: {"application date"},
+		},
+	},
+	&columnImporter{
+		format:      "linkedin_saved",
+		description: `LinkedIn data export "Saved Jobs" CSV, imported into the wishlist`,
+		signature:   []string{"saved date", "job url"},
+		columns: map[string][]string{
+			ImportCompany:    {"company name"},
+			ImportPosition:   {"job title"},
+			ImportPostingURL: {"job url"},
+		},
+		wishlist: true,
+	},
+	&columnImporter{
+		format:      "huntr",
+		description: "Huntr board export, the list a job is on is its status",
+		signature:   []string{"list"},
+		columns: map[string][]string{
+			ImportCompany:     {"company", "employer"},
+			ImportPosition:    {"job title", "title"},
+			ImportLocation:    {"location"},
+			ImportStatus:      {"list"},
+			ImportAppliedDate: {"date applied", "applied date"},
+			ImportPostingURL:  {"url", "job url"},
+			ImportNote:        {"notes", "description"},
+		},
+	},
+	&columnImporter{
+		format:      "teal",
+		description: "Teal job tracker export",
+		signature:   []string{"job position"},
+		columns: map[string][]string{
+			ImportCompany:     {"company"},
+			ImportPosition:    {"job position"},
+			ImportLocation:    {"location"},
+			ImportStatus:      {"status"},
+			ImportAppliedDate: {"date applied"},
+			ImportPostingURL:  {"url", "job url"},
+			ImportNote:        {"notes"},
+		},
+	},
+	&columnImporter{
+		format:      "spreadsheet",
+		description: "The common internship tracker spreadsheet: Company, Role, Location, Status, Date Applied, Link, Notes",
+		signature:   []string{"date applied"},
+		columns: map[string][]string{
+			ImportCompany:     {"company"},
+			ImportPosition:    {"role", "position"},
+			ImportLocation:    {"location"},
+			ImportStatus:      {"status"},
+			ImportAppliedDate: {"date applied"},
+			ImportTerm:        {"term", "season", "cycle"},
+			ImportPostingURL:  {"link", "job link", "url"},
+			ImportNote:        {"notes", "note", "comments"},
+		},
+	},
+	&columnImporter{
+		format:      "csv",
+		description: "Any CSV with company and position columns, matched by name",
+		columns: map[string][]string{
+			ImportCompany:     {"company", "employer", "organization"},
+			ImportPosition:    {"position", "role", "title", "job"},
+			ImportLocation:    {"location", "city"},
+			ImportStatus:      {"status", "stage"},
+			ImportAppliedDate: {"applied", "date"},
+			ImportTerm:        {"term", "season", "cycle"},
+			ImportPostingURL:  {"url", "link"},
+			ImportNote:        {"note", "comment"},
+		},
+		fuzzy: true,
+	},
+}
+
+// NewMappedImporter returns a generic CSV importer reading each field from
+// the header mapping names, e.g. {"company": "Employer"}
+func NewMappedImporter(mapping map[string]string) (Importer, error) {
+	columns := map[string][]string{}
+	for field, header := range mapping {
+		if !isImportField(field) {
+			return nil, errors.New("unknown field " + field)
+		}
+		if header = strings.TrimSpace(header); header != "" {
+			columns[field] = []string{header}
+		}
+	}
+	if columns[ImportCompany] == nil || columns[ImportPosition] == nil {
+		return nil, errors.New("the mapping needs company and position columns")
+	}
+	return &columnImporter{
+		format:      "custom",
+		description: "CSV with a column mapping",
+		columns:     columns,
+	}, nil
+}
+
+func isImportField(field string) bool {
+	for _, f := range ImportFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *columnImporter) Format() string {
+	return i.format
+}
+
+func (i *columnImporter) Description() string {
+	return i.description
+}
+
+// Detect scores one point per field found, plus ten per signature header.
+// Both company and position must be found.
+func (i *columnImporter) Detect(header []string) int {
+	for _, name := range i.signature {
+		if headerIndex(header, name) < 0 {
+			return 0
+		}
+	}
+	index := i.columnIndex(header)
+	if _, ok := index[ImportCompany]; !ok {
+		return 0
+	}
+	if _, ok := index[ImportPosition]; !ok {
+		return 0
+	}
+	return len(index) + 10*len(i.signature)
+}
+
+// columnIndex finds the column of each field. A column is only used for
+// one field, in ImportFields order.
+func (i *columnImporter) columnIndex(header []string) map[string]int {
+	index := map[string]int{}
+	used := map[int]bool{}
+	for _, field := range ImportFields {
+		for _, name := range i.columns[field] {
+			col := -1
+			if i.fuzzy {
+				for c, h := range header {
+					if !used[c] && strings.Contains(strings.ToLower(h), name) {
+						col = c
+						break
+					}
+				}
+			} else {
+				col = headerIndex(header, name)
+			}
+			if col >= 0 && !used[col] {
+				index[field] = col
+				used[col] = true
+				break
+			}
+		}
+	}
+	return index
+}
+
+func headerIndex(header []string, name string) int {
+	for c, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), name) {
+			return c
+		}
+	}
+	return -1
+}
+
+func (i *columnImporter) Read(header, row []string) ImportRecord {
+	index := i.columnIndex(header)
+	value := func(field string) string {
+		if col, ok := index[field]; ok && col < len(row) {
+			return strings.TrimSpace(row[col])
+		}
+		return ""
+	}
+
+	record := ImportRecord{
+		Company:    value(ImportCompany),
+		Position:   value(ImportPosition),
+		Location:   value(ImportLocation),
+		Term:       value(ImportTerm),
+		PostingURL: value(ImportPostingURL),
+		Note:       value(ImportNote),
+		Wishlist:   i.wishlist,
+	}
+	if record.Company == "" {
+		record.Errors = append(record.Errors, "Company is missing")
+	}
+	if record.Position == "" {
+		record.Errors = append(record.Errors, "Position is missing")
+	}
+	if record.PostingURL != "" && !IsHTTPURL(record.PostingURL) {
+		record.PostingURL = ""
+	}
+	if note := []rune(record.Note); len(note) > 1048 {
+		record.Note = string(note[:1048])
+	}
+	if season, year, ok := ParseTerm(record.Term); ok {
+		record.Term = TermName(season, year)
+	}
+
+	if status := value(ImportStatus); !i.wishlist {
+		var ok bool
+		if record.Status, record.Wishlist, ok = ParseImportStatus(status); !ok {
+			record.Warnings = append(record.Warnings, "Unknown status "+status+", imported as Applied")
+		}
+	}
+
+	date, ok := parseImportDate(value(ImportAppliedDate))
+	if !ok {
+		record.Errors = append(record.Errors, "Unrecognized date "+value(ImportAppliedDate))
+	}
+	record.AppliedDate = date
+	return record
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+)
+
+// Fields an import can fill, used as keys of column mappings
+const (
+	ImportCompany     = "company"
+	ImportPosition    = "position"
+	ImportLocation    = "location"
+	ImportStatus      = "status"
+	ImportAppliedDate = "applied_date"
+	ImportTerm        = "term"
+	ImportPostingURL  = "posting_url"
+	ImportNote        = "note"
+)
+
+// ImportFields lists the fields in the order generic CSV headers are
+// matched to them, more specific names first
+var ImportFields = []string{
+	ImportPostingURL, ImportAppliedDate, ImportCompany, ImportPosition,
+	ImportLocation, ImportStatus, ImportTerm, ImportNote,
+}
+
+// What happens to an imported row
+const (
+	ImportResultNew       = "new"
+	ImportResultDuplicate = "duplicate" // Already tracked, or an earlier row
+	ImportResultInvalid   = "invalid"
+)
+
+// Importer reads the rows of one tracker or export format. The formats
+// are CSV files whose header names the columns.
+type Importer interface {
+	// Format is the name clients pick the importer by
+	Format() string
+	Description() string
+	// Detect scores how well a header fits the format, 0 if it doesn't
+	Detect(header []string) int
+	// Read turns a row into a record, noting problems in its Errors and
+	// Warnings
+	Read(header, row []string) ImportRecord
+}
+
+// ImportRecord is one row read by an importer
+type ImportRecord struct {
+	Row         int                      `json:"row"` // Line in the file, 1 is the header
+	Company     string                   `json:"company"`
+	Position    string                   `json:"position"`
+	Location    string                   `json:"location,omitempty"`
+	Status      models.ApplicationStatus `json:"status"`
+	AppliedDate *time.Time               `json:"applied_date,omitempty"`
+	Term        string                   `json:"term,omitempty"`
+	PostingURL  string                   `json:"posting_url,omitempty"`
+	Note        string                   `json:"note,omitempty"`
+	Wishlist    bool                     `json:"wishlist"`           // Not applied to yet, goes to the wishlist
+	Errors      []string                 `json:"errors,omitempty"`   // The row can't be imported
+	Warnings    []string                 `json:"warnings,omitempty"` // The row is imported, but not as written
+}
+
+// ImportRow is a record with what importing it does
+type ImportRow struct {
+	ImportRecord
+	Result         string `json:"result"` // new, duplicate or invalid
+	ApplicationID  *uint  `json:"application_id,omitempty"`
+	WishlistItemID *uint  `json:"wishlist_item_id,omitempty"`
+}
+
+// ImportPreview is a file as an import would see it
+type ImportPreview struct {
+	Format string         `json:"format"`
+	Header []string       `json:"header"`
+	Rows   []ImportRow    `json:"rows"`
+	Counts map[string]int `json:"counts"`
+}
+
+// FindImporter returns the importer for a format, or nil
+func FindImporter(format string) Importer {
+	for _, importer := range importers {
+		if importer.Format() == format {
+			return importer
+		}
+	}
+	return nil
+}
+
+// Importers lists the built-in formats, most specific first
+func Importers() []Importer {
+	return importers
+}
+
+// DetectImporter picks the format whose columns fit the header best, or
+// returns nil if none does
+func DetectImporter(header []string) Importer {
+	var best Importer
+	bestScore := 0
+	for _, importer := range importers {
+		if score := importer.Detect(header); score > bestScore {
+			best, bestScore = importer, score
+		}
+	}
+	return best
+}
+
+// ReadImport reads a CSV file with importer, or the detected format when
+// importer is nil. Files with more than maxRows rows are rejected.
+func ReadImport(r io.Reader, importer Importer, maxRows int) (*ImportPreview, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	if importer == nil {
+		if importer = DetectImporter(header); importer == nil {
+			return nil, errors.New("unrecognized columns, pick a format or map the columns")
+		}
+	} else if importer.Detect(header) == 0 {
+		return nil, fmt.Errorf("the columns don't fit the %s format", importer.Format())
+	}
+
+	preview := &ImportPreview{Format: importer.Format(), Header: header, Rows: []ImportRow{}, Counts: map[string]int{}}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isBlankRow(row) {
+			continue
+		}
+		if len(preview.Rows) == maxRows {
+			return nil, fmt.Errorf("the file has more than %d rows", maxRows)
+		}
+
+		record := importer.Read(header, row)
+		record.Row, _ = reader.FieldPos(0)
+		preview.Rows = append(preview.Rows, ImportRow{ImportRecord: record})
+	}
+	return preview, nil
+}
+
+func isBlankRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffImport sets what importing each row does: rows with errors are
+// invalid, rows matching an application or wishlist item of the user (or
+// an earlier row) by company, position and term are duplicates.
+func DiffImport(db *gorm.DB, userID uint, preview *ImportPreview) error {
+	var apps []models.Application
+	if err := db.Select("id, company, position, term").Where("user_id = ?", userID).Find(&apps).Error; err != nil {
+		return err
+	}
+	var items []models.WishlistItem
+	if err := db.Where("user_id = ?", userID).Find(&items).Error; err != nil {
+		return err
+	}
+
+	applied := make(map[string]uint, len(apps))
+	for i := range apps {
+		applied[applicationKey(&apps[i])] = apps[i].ID
+	}
+	wishlist := make(map[string]uint, len(items))
+	for i := range items {
+		wishlist[wishlistKey(&items[i])] = items[i].ID
+	}
+
+	seen := make(map[string]bool, len(preview.Rows))
+	for i := range preview.Rows {
+		row := &preview.Rows[i]
+		key := applicationKey(&models.Application{Company: row.Company, Position: row.Position, Term: row.Term})
+		switch {
+		case len(row.Errors) > 0:
+			row.Result = ImportResultInvalid
+		case seen[key]:
+			row.Result = ImportResultDuplicate
+		default:
+			row.Result = ImportResultNew
+			if id, ok := applied[key]; ok {
+				row.Result, row.ApplicationID = ImportResultDuplicate, &id
+			} else if id, ok := wishlist[key]; ok && row.Wishlist {
+				row.Result, row.WishlistItemID = ImportResultDuplicate, &id
+			}
+		}
+		seen[key] = true
+		preview.Counts[row.Result]++
+	}
+	return nil
+}
+
+// ParseImportStatus reads a status as trackers name it. Stages before
+// applying, like "Bookmarked", are reported as wishlist.
+func ParseImportStatus(value string) (status models.ApplicationStatus, wishlist bool, ok bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "":
+		return models.StatusApplied, false, true
+	case containsAny(value, "wishlist", "bookmark", "saved", "to apply", "applying", "interested", "not applied"):
+		return models.StatusApplied, true, true
+	case containsAny(value, "reject", "declined", "not selected", "closed", "ghosted", "withdrawn"):
+		return models.StatusRejected, false, true
+	case containsAny(value, "offer", "accepted", "negotiat"):
+		return models.StatusAccepted, false, true
+	case containsAny(value, "interview", "screen", "onsite", "final round", "superday"):
+		return models.StatusInterviewing, false, true
+	case value == "oa" || containsAny(value, "assessment", "coding challenge", "hackerrank", "codesignal", "take home"):
+		return models.StatusOAReceived, false, true
+	case containsAny(value, "applied", "submitted", "in review", "under review", "pending"):
+		return models.StatusApplied, false, true
+	}
+	return models.StatusApplied, false, false
+}
+
+func containsAny(value string, words ...string) bool {
+	for _, word := range words {
+		if strings.Contains(value, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// importDateLayouts are the date formats found in tracker exports
+var importDateLayouts = []string{
+	"2006-01-02", time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04",
+	"1/2/2006", "1/2/06", "1/2/06, 3:04 PM", "1/2/2006 15:04:05", "1/2/2006 3:04 PM",
+	"Jan 2, 2006", "January 2, 2006", "2 Jan 2006", "Jan 2 2006",
+}
+
+// parseImportDate reads a date in any of importDateLayouts
+func parseImportDate(value string) (*time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, true
+	}
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,511 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultEmailRules classify emails when none of the user's rules match.
+// Rejections come first, as they often mention interviews or offers.
+var DefaultEmailRules = []models.EmailRule{
+	{
+		Name:     "Rejection",
+		Field:    models.RuleFieldAny,
+		Category: models.EmailRejection,
+		Pattern: `unfortunately|regret to inform|not (be )?(moving|move) forward|decided to (move|proceed|pursue) ` +
+			`(forward )?with other|other candidates|no longer (being )?considered|position has (been|now been) filled|not selected`,
+	},
+	{
+		Name:     "Offer",
+		Field:    models.RuleFieldAny,
+		Category: models.EmailOffer,
+		Pattern:  `pleased to (offer|extend)|offer letter|extend (you )?an offer|congratulations.{0,60}offer`,
+	},
+	{
+		Name:     "Online assessment",
+		Field:    models.RuleFieldAny,
+		Category: models.EmailOA,
+		Pattern:  `online assessment|coding (challenge|assessment)|technical assessment|hackerrank|codesignal|codility|hirevue|take[- ]home`,
+	},
+	{
+		Name:     "Interview invitation",
+		Field:    models.RuleFieldAny,
+		Category: models.EmailInterview,
+		Pattern:  `interview|phone screen|next round|superday|schedule (a|your) (call|time|chat)|your availability`,
+	},
+}
+
+// emailServiceDomains send email on behalf of many companies, so their
+// domain says nothing about the company
+var emailServiceDomains = []string{
+	"greenhouse.io", "greenhouse-mail.io", "lever.co", "myworkday.com", "myworkdayjobs.com", "workday.com",
+	"ashbyhq.com", "smartrecruiters.com", "icims.com", "jobvite.com", "successfactors.com", "taleo.net",
+	"workable.com", "breezy.hr", "bamboohr.com", "recruitee.com", "rippling.com", "eightfold.ai", "avature.net",
+	"hackerrank.com", "hackerrankforwork.com", "codesignal.com", "codility.com", "hirevue.com", "linkedin.com",
+	"gmail.com", "googlemail.com", "outlook.com", "hotmail.com", "yahoo.com", "icloud.com",
+}
+
+// ValidateEmailRule checks a rule's field, category and pattern
+func ValidateEmailRule(rule *models.EmailRule) error {
+	switch rule.Field {
+	case models.RuleFieldSubject, models.RuleFieldBody, models.RuleFieldFrom, models.RuleFieldAny:
+	default:
+		return errors.New("field must be subject, body, from or any")
+	}
+	if rule.Category != models.EmailOther && !containsString(models.EmailCategories, rule.Category) {
+		return errors.New("category must be rejection, oa, interview, offer or other")
+	}
+	if _, err := regexp.Compile("(?i)" + rule.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %v", err)
+	}
+	return nil
+}
+
+// GetEmailIngestionSettings returns the user's settings, creating them with
+// a new token the first time
+func GetEmailIngestionSettings(db *gorm.DB, userID uint) (*models.EmailIngestionSettings, error) {
+	var settings models.EmailIngestionSettings
+	err := db.Where("user_id = ?", userID).First(&settings).Error
+	if err == nil {
+		return &settings, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	token := make([]byte, 8)
+	rand.Read(token)
+	settings = models.EmailIngestionSettings{
+		UserID:          userID,
+		Token:           hex.EncodeToString(token),
+		UseDefaultRules: true,
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&settings).Error; err != nil {
+		return nil, err
+	}
+	// Another request may have created them first
+	if err := db.Where("user_id = ?", userID).First(&settings).Error; err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// IngestionAddress is the address the user forwards emails to, or "" when
+// IMAP_ADDRESS isn't set
+func IngestionAddress(settings *models.EmailIngestionSettings) string {
+	cfg, _ := config.GetIMAPConfig()
+	local, domain, ok := strings.Cut(cfg.Address, "@")
+	if !ok {
+		return ""
+	}
+	return local + "+" + settings.Token + "@" + domain
+}
+
+// FindIngestionUser finds who a message in the shared mailbox is for: the
+// user whose token is the plus address it was sent to. The sender isn't
+// trusted, as anyone can put a user's address in From.
+func FindIngestionUser(db *gorm.DB, email *ParsedEmail) (uint, bool) {
+	for _, recipient := range email.Recipients {
+		local, _, _ := strings.Cut(recipient, "@")
+		if _, token, ok := strings.Cut(local, "+"); ok && token != "" {
+			var settings models.EmailIngestionSettings
+			if err := db.Where("token = ?", token).First(&settings).Error; err == nil {
+				return settings.UserID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// ClassifyEmail returns the category of the first rule the email matches,
+// with the rule's name, or EmailOther
+func ClassifyEmail(email *ParsedEmail, rules []models.EmailRule) (string, string) {
+	for _, rule := range rules {
+		pattern, err := regexp.Compile("(?i)" + rule.Pattern)
+		if err != nil {
+			continue
+		}
+
+		var text string
+		switch rule.Field {
+		case models.RuleFieldSubject:
+			text = email.Subject
+		case models.RuleFieldBody:
+			text = email.Body
+		case models.RuleFieldFrom:
+			text = email.FromName + " <" + email.FromAddress + ">"
+		default:
+			text = email.Subject + "\n" + email.Body
+		}
+		if pattern.MatchString(text) {
+			return rule.Category, rule.Name
+		}
+	}
+	return models.EmailOther, ""
+}
+
+// emailRules returns the user's enabled rules by priority, followed by the
+// built-in ones if the user uses them
+func emailRules(db *gorm.DB, settings *models.EmailIngestionSettings) ([]models.EmailRule, error) {
+	var rules []models.EmailRule
+	if err := db.Where("user_id = ? AND enabled = ?", settings.UserID, true).
+		Order("priority ASC, id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	if settings.UseDefaultRules {
+		rules = append(rules, DefaultEmailRules...)
+	}
+	return rules, nil
+}
+
+// emailCandidate is a company the user applied to, with the names it goes by
+type emailCandidate struct {
+	names   []string // Normalized
+	website string   // Domain of the company's website
+	apps    []models.Application
+}
+
+// MatchEmailApplication finds the application an email is about. The
+// sender's domain and the company named in the sender, subject or body
+// pick the company; among its applications the one whose position the
+// email mentions wins, then active ones, then the latest. It returns nil
+// when no company matches.
+func MatchEmailApplication(db *gorm.DB, userID uint, email *ParsedEmail) (*models.Application, string, error) {
+	var apps []models.Application
+	if err := db.Where("user_id = ?", userID).Order("applied_date DESC").Find(&apps).Error; err != nil {
+		return nil, "", err
+	}
+	var companies []models.Company
+	if err := db.Where("user_id = ?", userID).Find(&companies).Error; err != nil {
+		return nil, "", err
+	}
+	byID := make(map[uint]*models.Company, len(companies))
+	for i := range companies {
+		byID[companies[i].ID] = &companies[i]
+	}
+
+	candidates := map[string]*emailCandidate{}
+	for _, app := range apps {
+		key := NormalizeCompanyName(app.Company)
+		if app.CompanyID != nil {
+			key = fmt.Sprintf("#%d", *app.CompanyID)
+		}
+		candidate := candidates[key]
+		if candidate == nil {
+			candidate = &emailCandidate{}
+			if company := byID[derefID(app.CompanyID)]; company != nil {
+				for _, name := range CompanyNames(company) {
+					candidate.names = append(candidate.names, NormalizeCompanyName(name))
+				}
+				if parsed, err := url.Parse(company.Website); err == nil {
+					candidate.website = strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+				}
+			}
+			candidate.names = append(candidate.names, NormalizeCompanyName(app.Company))
+			candidates[key] = candidate
+		}
+		candidate.apps = append(candidate.apps, app)
+	}
+
+	domain := EmailDomain(email.FromAddress)
+	fromService := false
+	for _, service := range emailServiceDomains {
+		if domain == service || strings.HasSuffix(domain, "."+service) {
+			fromService = true
+		}
+	}
+	fromName := " " + emailWords(email.FromName) + " "
+	subject := " " + emailWords(email.Subject) + " "
+	body := " " + emailWords(email.Body) + " "
+
+	var best *emailCandidate
+	bestScore, bestReason := 0, ""
+	for _, candidate := range candidates {
+		score, reason := 0, ""
+		if !fromService && domain != "" {
+			if candidate.website != "" && (domain == candidate.website || strings.HasSuffix(domain, "."+candidate.website)) {
+				score, reason = 4, "sender domain "+domain+" is the company's website"
+			} else if domainMatchesNames(domain, candidate.names) {
+				score, reason = 3, "sender domain "+domain
+			}
+		}
+		for _, name := range candidate.names {
+			if len(name) < 3 {
+				continue // Too short to find in text reliably
+			}
+			word := " " + name + " "
+			switch {
+			case strings.Contains(fromName, word) && score < 2:
+				score, reason = 2, "company named in the sender"
+			case strings.Contains(subject, word) && score < 2:
+				score, reason = 2, "company named in the subject"
+			case strings.Contains(body, word) && score < 1:
+				score, reason = 1, "company named in the email"
+			}
+		}
+		if score > bestScore {
+			best, bestScore, bestReason = candidate, score, reason
+		}
+	}
+	if best == nil {
+		return nil, "", nil
+	}
+
+	sort.SliceStable(best.apps, func(i, j int) bool {
+		a, b := &best.apps[i], &best.apps[j]
+		if ma, mb := mentionsPosition(a, subject, body), mentionsPosition(b, subject, body); ma != mb {
+			return ma
+		}
+		if a.Archived != b.Archived {
+			return !a.Archived
+		}
+		return a.AppliedDate.After(b.AppliedDate)
+	})
+	app := best.apps[0]
+	if len(best.apps) > 1 && mentionsPosition(&app, subject, body) {
+		bestReason += ", position named in the email"
+	}
+	return &app, bestReason, nil
+}
+
+func derefID(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// emailWords lowercases text and turns punctuation into spaces, so names
+// can be looked up as whole words
+func emailWords(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	}), " ")
+}
+
+// domainMatchesNames reports whether a domain like "careers.acme-corp.com"
+// is named after one of the company names
+func domainMatchesNames(domain string, names []string) bool {
+	labels := strings.Split(domain, ".")
+	for _, label := range labels[:len(labels)-1] {
+		label = strings.ReplaceAll(label, "-", "")
+		for _, name := range names {
+			if len(name) >= 3 && label == strings.ReplaceAll(name, " ", "") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mentionsPosition(app *models.Application, subject, body string) bool {
+	position := emailWords(app.Position)
+	return position != "" && (strings.Contains(subject, " "+position+" ") || strings.Contains(body, " "+position+" "))
+}
+
+// EmailCategoryStatus is the status an email category moves an application to
+func EmailCategoryStatus(category string) (models.ApplicationStatus, bool) {
+	switch category {
+	case models.EmailRejection:
+		return models.StatusRejected, true
+	case models.EmailOA:
+		return models.StatusOAReceived, true
+	case models.EmailInterview:
+		return models.StatusInterviewing, true
+	case models.EmailOffer:
+		return models.StatusAccepted, true
+	}
+	return 0, false
+}
+
+// statusAdvances reports whether moving from current to next is progress.
+// Accepted and rejected applications stay where they are, and a rejection
+// can end any other status.
+func statusAdvances(current, next models.ApplicationStatus) bool {
+	if current == models.StatusAccepted || current == models.StatusRejected || current == next {
+		return false
+	}
+	return next == models.StatusRejected || next > current
+}
+
+// AnalyzeEmail classifies an email and matches it to one of the user's
+// applications, returning the inbound email it would be saved as
+func AnalyzeEmail(db *gorm.DB, settings *models.EmailIngestionSettings, email *ParsedEmail, source string) (*models.InboundEmail, error) {
+	rules, err := emailRules(db, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	inbound := &models.InboundEmail{
+		UserID:      settings.UserID,
+		MessageID:   truncate(email.MessageID, 255),
+		Source:      source,
+		FromAddress: email.FromAddress,
+		FromName:    email.FromName,
+		Subject:     email.Subject,
+		Snippet:     truncate(strings.Join(strings.Fields(email.Body), " "), 300),
+		ReceivedAt:  email.Date,
+		State:       models.InboundIgnored,
+	}
+	inbound.Category, inbound.MatchedRule = ClassifyEmail(email, rules)
+	status, ok := EmailCategoryStatus(inbound.Category)
+	if !ok {
+		return inbound, nil
+	}
+
+	app, reason, err := MatchEmailApplication(db, settings.UserID, email)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		inbound.State = models.InboundUnmatched
+		return inbound, nil
+	}
+
+	inbound.ApplicationID = &app.ID
+	inbound.Application = app
+	inbound.MatchReason = reason
+	if statusAdvances(app.Status, status) {
+		inbound.ProposedStatus = &status
+		inbound.State = models.InboundProposed
+	} else {
+		inbound.MatchReason += ", already " + app.Status.String()
+	}
+	return inbound, nil
+}
+
+// IngestEmail saves an email for the user, making the status change it
+// proposes right away if the user has auto-apply on. An email that was
+// ingested before is returned as it is, with created false.
+func IngestEmail(userID uint, email *ParsedEmail, source string) (*models.InboundEmail, bool, error) {
+	var existing models.InboundEmail
+	if err := config.DB.Where("user_id = ? AND message_id = ?", userID, truncate(email.MessageID, 255)).
+		First(&existing).Error; err == nil {
+		return &existing, false, nil
+	}
+
+	settings, err := GetEmailIngestionSettings(config.DB, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	inbound, err := AnalyzeEmail(config.DB, settings, email, source)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		app := inbound.Application
+		inbound.Application = nil
+		if err := tx.Create(inbound).Error; err != nil {
+			return err
+		}
+		if settings.AutoApply && inbound.State == models.InboundProposed {
+			if err := ApplyInboundEmail(tx, inbound, app); err != nil {
+				return err
+			}
+		}
+		inbound.Application = app
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return inbound, true, nil
+}
+
+// ApplyInboundEmail makes the status change an inbound email proposes to
+// app, as part of tx
+func ApplyInboundEmail(tx *gorm.DB, inbound *models.InboundEmail, app *models.Application) error {
+	if inbound.ProposedStatus == nil {
+		return errors.New("the email doesn't propose a status change")
+	}
+	if err := SetApplicationStatus(tx, app, *inbound.ProposedStatus); err != nil {
+		return err
+	}
+	inbound.ApplicationID = &app.ID
+	inbound.State = models.InboundApplied
+	return tx.Model(inbound).Updates(map[string]interface{}{
+		"application_id": app.ID,
+		"state":          models.InboundApplied,
+	}).Error
+}
+
+// StartEmailIngestionWorker polls the IMAP mailbox every
+// IMAP_POLL_INTERVAL for unseen messages and ingests them for the users
+// whose plus address they were sent to. It does nothing unless IMAP_HOST
+// is set.
+func StartEmailIngestionWorker() {
+	cfg, ok := config.GetIMAPConfig()
+	if !ok {
+		return
+	}
+	interval := config.GetEnvDuration("IMAP_POLL_INTERVAL", 5*time.Minute)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := pollMailbox(cfg); err != nil {
+				log.Printf("Email ingestion: failed to poll %s: %v", cfg.Host, err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// pollMailbox ingests the unseen messages of the mailbox. Messages are
+// marked seen once handled; ones that failed to save are left unseen to be
+// retried on the next poll.
+func pollMailbox(cfg config.IMAPConfig) error {
+	client, err := dialIMAP(cfg)
+	if err != nil {
+		return err
+	}
+	defer client.logout()
+
+	uids, err := client.searchUnseen()
+	if err != nil {
+		return err
+	}
+	if len(uids) > 100 {
+		uids = uids[:100]
+	}
+
+	for _, uid := range uids {
+		raw, err := client.fetch(uid)
+		if err != nil {
+			return err
+		}
+
+		email, err := ParseEmail(bytes.NewReader(raw))
+		if err != nil {
+			log.Printf("Email ingestion: skipping unreadable message %d: %v", uid, err)
+		} else if userID, ok := FindIngestionUser(config.DB, email); !ok {
+			log.Printf("Email ingestion: skipping message %d from %s, it isn't for any user", uid, email.FromAddress)
+		} else if _, _, err := IngestEmail(userID, email, models.InboundSourceIMAP); err != nil {
+			log.Printf("Email ingestion: failed to ingest message %d: %v", uid, err)
+			continue
+		}
+
+		if err := client.markSeen(uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JWK is a JSON Web Key as found in a JWKS document
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKey decodes the JWK into an RSA, ECDSA or Ed25519 public key
+func (jwk *JWK) PublicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+// NewJWK encodes an RSA or Ed25519 public key as a JWK
+func NewJWK(kid string, key crypto.PublicKey) (JWK, error) {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   encode(k.N.Bytes()),
+			E:   encode(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: "EdDSA",
+			Crv: "Ed25519",
+			X:   encode(k),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("unsupported key type %T", key)
+	}
+}
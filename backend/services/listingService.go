@@ -0,0 +1,341 @@
+package services
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+)
+
+// Listing is one row of a community internship list
+type Listing struct {
+	Line       int        `json:"line"` // In the markdown file
+	Company    string     `json:"company"`
+	Role       string     `json:"role"`
+	Location   string     `json:"location"`
+	URL        string     `json:"url"`
+	DatePosted *time.Time `json:"date_posted,omitempty"`
+	Closed     bool       `json:"closed"` // Marked with 🔒
+}
+
+// What an imported listing is compared to the user's data
+const (
+	ListingNew        = "new"
+	ListingOnWishlist = "on_wishlist"
+	ListingApplied    = "applied"
+	ListingClosed     = "closed"
+	ListingDuplicate  = "duplicate" // Listed more than once in the file
+)
+
+// ListingDiff is a listing with what it matched
+type ListingDiff struct {
+	Listing
+	Status         string `json:"status"`
+	WishlistItemID *uint  `json:"wishlist_item_id,omitempty"`
+	ApplicationID  *uint  `json:"application_id,omitempty"`
+}
+
+// listingColumns are the table columns the importer reads, found by header
+type listingColumns struct {
+	company, role, location, link, date int
+}
+
+var (
+	markdownLinkPattern = regexp.MustCompile(`(!?)\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	hrefPattern         = regexp.MustCompile(`(?i)<a\s[^>]*href="([^"]+)"`)
+	htmlBreakPattern    = regexp.MustCompile(`(?i)<br\s*/?>|</br>`)
+	htmlTagPattern      = regexp.MustCompile(`<[^>]*>`)
+	summaryPattern      = regexp.MustCompile(`(?is)<summary>.*?</summary>`)
+	ageCellPattern      = regexp.MustCompile(`^(\d+)\s*(d|mo)$`)
+)
+
+// ParseMarkdownListings reads the tables of a community internship list,
+// like the ones published on GitHub. Columns are found by their headers:
+// company, role (or position, title), location, application (or link) and
+// date posted (or age). A company of "↳" repeats the previous row's
+// company and 🔒 marks a closed posting. now resolves ages like "3d" and
+// dates without a year.
+func ParseMarkdownListings(r io.Reader, now time.Time) ([]Listing, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var listings []Listing
+	var columns *listingColumns
+	var header []string
+	previousCompany := ""
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(text, "|") {
+			columns, header = nil, nil
+			continue
+		}
+		cells := splitTableRow(text)
+
+		if columns == nil {
+			if header == nil {
+				header = cells
+				continue
+			}
+			// The row after the header must be the |---| separator
+			if !isSeparatorRow(cells) {
+				header = cells
+				continue
+			}
+			columns = findListingColumns(header)
+			previousCompany = ""
+			if columns == nil {
+				header = nil // Not a listing table, skip its rows
+				columns = &listingColumns{company: -1}
+			}
+			continue
+		}
+		if columns.company < 0 {
+			continue
+		}
+
+		listing := Listing{
+			Line:     line,
+			Company:  markdownText(cell(cells, columns.company)),
+			Role:     markdownText(cell(cells, columns.role)),
+			Location: markdownText(cell(cells, columns.location)),
+			URL:      markdownURL(cell(cells, columns.link)),
+			Closed:   strings.Contains(text, "🔒"),
+		}
+		if listing.Company == "↳" || listing.Company == "" {
+			listing.Company = previousCompany
+		}
+		if listing.URL == "" {
+			listing.URL = markdownURL(cell(cells, columns.role))
+		}
+		listing.DatePosted = parseListingDate(markdownText(cell(cells, columns.date)), now)
+		if listing.Company == "" || listing.Role == "" {
+			continue
+		}
+		previousCompany = listing.Company
+		listings = append(listings, listing)
+	}
+	return listings, scanner.Err()
+}
+
+func splitTableRow(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	row = strings.ReplaceAll(row, `\|`, "\x00")
+	cells := strings.Split(row, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(strings.ReplaceAll(cells[i], "\x00", "|"))
+	}
+	return cells
+}
+
+func isSeparatorRow(cells []string) bool {
+	for _, c := range cells {
+		if strings.Trim(c, ":- ") != "" || !strings.Contains(c, "-") {
+			return false
+		}
+	}
+	return len(cells) > 0
+}
+
+func findListingColumns(header []string) *listingColumns {
+	columns := &listingColumns{company: -1, role: -1, location: -1, link: -1, date: -1}
+	for i, h := range header {
+		h = strings.ToLower(markdownText(h))
+		switch {
+		case strings.Contains(h, "company"):
+			columns.company = i
+		case strings.Contains(h, "role") || strings.Contains(h, "position") || strings.Contains(h, "title"):
+			columns.role = i
+		case strings.Contains(h, "location"):
+			columns.location = i
+		case strings.Contains(h, "application") || strings.Contains(h, "link") || strings.Contains(h, "apply"):
+			columns.link = i
+		case strings.Contains(h, "date") || strings.Contains(h, "posted") || h == "age":
+			columns.date = i
+		}
+	}
+	if columns.company < 0 || columns.role < 0 {
+		return nil
+	}
+	return columns
+}
+
+func cell(cells []string, i int) string {
+	if i < 0 || i >= len(cells) {
+		return ""
+	}
+	return cells[i]
+}
+
+// markdownText strips links, HTML and emoji markers from a table cell.
+// Line breaks, used to list several locations, become "; " and the
+// summary of a collapsed list ("5 locations") is dropped.
+func markdownText(value string) string {
+	value = markdownLinkPattern.ReplaceAllStringFunc(value, func(link string) string {
+		m := markdownLinkPattern.FindStringSubmatch(link)
+		if m[1] == "!" {
+			return "" // Images, e.g. apply buttons
+		}
+		return m[2]
+	})
+	value = htmlBreakPattern.ReplaceAllString(value, "; ")
+	value = summaryPattern.ReplaceAllString(value, "")
+	value = htmlTagPattern.ReplaceAllString(value, "")
+	value = strings.NewReplacer("**", "", "__", "", "`", "").Replace(value)
+
+	value = strings.Map(func(r rune) rune {
+		if r == '↳' {
+			return r
+		}
+		// Emoji markers like 🛂 and 🇺🇸, with their joiners
+		if unicode.Is(unicode.So, r) || r == '\uFE0F' || r == '\u200D' {
+			return -1
+		}
+		return r
+	}, value)
+	value = strings.Join(strings.Fields(value), " ")
+	return strings.Trim(value, "; ")
+}
+
+// markdownURL returns the first link in a cell that isn't an image
+func markdownURL(value string) string {
+	if m := hrefPattern.FindStringSubmatch(value); m != nil && IsHTTPURL(m[1]) {
+		return m[1]
+	}
+	for _, m := range markdownLinkPattern.FindAllStringSubmatch(value, -1) {
+		if m[1] == "" && IsHTTPURL(m[3]) {
+			return m[3]
+		}
+	}
+	return ""
+}
+
+// parseListingDate reads "2025-09-05", "Sep 05" (the latest such date up
+// to now) or an age like "3d" or "2mo"
+func parseListingDate(value string, now time.Time) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return &t
+	}
+	if m := ageCellPattern.FindStringSubmatch(strings.ToLower(value)); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if m[2] == "mo" {
+			day = day.AddDate(0, -n, 0)
+		} else {
+			day = day.AddDate(0, 0, -n)
+		}
+		return &day
+	}
+	for _, layout := range []string{"Jan 02", "Jan 2", "January 2", "Jan 02, 2006", "Jan 2, 2006", "January 2, 2006"} {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if t.Year() == 0 {
+			t = t.AddDate(now.Year(), 0, 0)
+			if t.After(now) {
+				t = t.AddDate(-1, 0, 0)
+			}
+		}
+		return &t
+	}
+	return nil
+}
+
+// DiffListings compares listings to the user's wishlist and applications.
+// A listing matches by posting URL or by company and role.
+func DiffListings(db *gorm.DB, userID uint, listings []Listing) ([]ListingDiff, error) {
+	var items []models.WishlistItem
+	if err := db.Where("user_id = ?", userID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	var apps []models.Application
+	if err := db.Select("id, company, position, posting_url").Where("user_id = ?", userID).Find(&apps).Error; err != nil {
+		return nil, err
+	}
+
+	wishlist := make(map[string]uint, len(items)*2)
+	for _, item := range items {
+		for _, key := range listingKeys(item.Company, item.Position, item.PostingURL) {
+			wishlist[key] = item.ID
+		}
+	}
+	applied := make(map[string]uint, len(apps)*2)
+	for _, app := range apps {
+		for _, key := range listingKeys(app.Company, app.Position, app.PostingURL) {
+			applied[key] = app.ID
+		}
+	}
+
+	seen := make(map[string]bool, len(listings))
+	diffs := make([]ListingDiff, 0, len(listings))
+	for _, listing := range listings {
+		diff := ListingDiff{Listing: listing, Status: ListingNew}
+		keys := listingKeys(listing.Company, listing.Role, listing.URL)
+		for _, key := range keys {
+			if id, ok := applied[key]; ok {
+				diff.Status, diff.ApplicationID = ListingApplied, &id
+				break
+			}
+			if id, ok := wishlist[key]; ok {
+				diff.Status, diff.WishlistItemID = ListingOnWishlist, &id
+			}
+		}
+		if diff.Status == ListingNew {
+			for _, key := range keys {
+				if seen[key] {
+					diff.Status = ListingDuplicate
+				}
+			}
+		}
+		if diff.Status == ListingNew && listing.Closed {
+			diff.Status = ListingClosed
+		}
+		for _, key := range keys {
+			seen[key] = true
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// listingKeys identifies a posting by its URL and by company and role
+func listingKeys(company, role, postingURL string) []string {
+	keys := []string{"role:" + NormalizeCompanyName(company) + "\x00" + strings.ToLower(strings.Join(strings.Fields(role), " "))}
+	if key := postingURLKey(postingURL); key != "" {
+		keys = append(keys, "url:"+key)
+	}
+	return keys
+}
+
+// postingURLKey drops the scheme, tracking parameters and trailing slash
+// that differ between copies of the same posting link
+func postingURLKey(value string) string {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	query := parsed.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || key == "ref" || key == "source" {
+			query.Del(key)
+		}
+	}
+	key := strings.ToLower(strings.TrimPrefix(parsed.Host, "www.")) + strings.TrimSuffix(parsed.Path, "/")
+	if encoded := query.Encode(); encoded != "" {
+		key += "?" + encoded
+	}
+	return key
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+)
+
+// Location is the structured form of a free-text location
+type Location struct {
+	City     string          `json:"city"`
+	Region   string          `json:"region"`
+	Country  string          `json:"country"`
+	WorkMode models.WorkMode `json:"work_mode"`
+}
+
+var usStates = map[string]string{
+	"al": "alabama", "ak": "alaska", "az": "arizona", "ar": "arkansas", "ca": "california",
+	"co": "colorado", "ct": "connecticut", "de": "delaware", "dc": "district of columbia",
+	"fl": "florida", "ga": "georgia", "hi": "hawaii", "id": "idaho", "il": "illinois",
+	"in": "indiana", "ia": "iowa", "ks": "kansas", "ky": "kentucky", "la": "louisiana",
+	"me": "maine", "md": "maryland", "ma": "massachusetts", "mi": "michigan", "mn": "minnesota",
+	"ms": "mississippi", "mo": "missouri", "mt": "montana", "ne": "nebraska", "nv": "nevada",
+	"nh": "new hampshire", "nj": "new jersey", "nm": "new mexico", "ny": "new york",
+	"nc": "north carolina", "nd": "north dakota", "oh": "ohio", "ok": "oklahoma", "or": "oregon",
+	"pa": "pennsylvania", "ri": "rhode island", "sc": "south carolina", "sd": "south dakota",
+	"tn": "tennessee", "tx": "texas", "ut": "utah", "vt": "vermont", "va": "virginia",
+	"wa": "washington", "wv": "west virginia", "wi": "wisconsin", "wy": "wyoming",
+}
+
+var caProvinces = map[string]string{
+	"ab": "alberta", "bc": "british columbia", "mb": "manitoba", "nb": "new brunswick",
+	"nl": "newfoundland and labrador", "ns": "nova scotia", "nt": "northwest territories",
+	"nu": "nunavut", "on": "ontario", "pe": "prince edward island", "qc": "quebec",
+	"sk": "saskatchewan", "yt": "yukon",
+}
+
+// Country names and abbreviations, lowercase, to ISO 3166-1 alpha-2 codes
+var countryCodes = map[string]string{
+	"us": "US", "usa": "US", "u.s.": "US", "u.s.a.": "US", "united states": "US", "united states of america": "US", "america": "US",
+	"uk": "GB", "u.k.": "GB", "united kingdom": "GB", "great britain": "GB", "england": "GB", "scotland": "GB",
+	"canada": "CA", "ireland": "IE", "germany": "DE", "france": "FR", "netherlands": "NL", "spain": "ES", "portugal": "PT",
+	"switzerland": "CH", "sweden": "SE", "poland": "PL", "italy": "IT", "israel": "IL", "india": "IN",
+	"singapore": "SG", "japan": "JP", "china": "CN", "australia": "AU", "mexico": "MX", "brazil": "BR",
+}
+
+// isoCountries are the codes in countryCodes, which locations also use
+var isoCountries = map[string]bool{}
+
+func init() {
+	for _, code := range countryCodes {
+		isoCountries[code] = true
+	}
+}
+
+// maxLocationPart is the size of the city and region columns
+const maxLocationPart = 100
+
+// Well-known cities and their common nicknames
+var knownCities = map[string]Location{
+	"nyc":           {City: "New York", Region: "NY", Country: "US"},
+	"new york":      {City: "New York", Region: "NY", Country: "US"},
+	"new york city": {City: "New York", Region: "NY", Country: "US"},
+	"manhattan":     {City: "New York", Region: "NY", Country: "US"},
+	"brooklyn":      {City: "New York", Region: "NY", Country: "US"},
+	"sf":            {City: "San Francisco", Region: "CA", Country: "US"},
+	"san francisco": {City: "San Francisco", Region: "CA", Country: "US"},
+	"bay area":      {City: "San Francisco", Region: "CA", Country: "US"},
+	"sf bay area":   {City: "San Francisco", Region: "CA", Country: "US"},
+	"la":            {City: "Los Angeles", Region: "CA", Country: "US"},
+	"los angeles":   {City: "Los Angeles", Region: "CA", Country: "US"},
+	"san jose":      {City: "San Jose", Region: "CA", Country: "US"},
+	"mountain view": {City: "Mountain View", Region: "CA", Country: "US"},
+	"palo alto":     {City: "Palo Alto", Region: "CA", Country: "US"},
+	"menlo park":    {City: "Menlo Park", Region: "CA", Country: "US"},
+	"sunnyvale":     {City: "Sunnyvale", Region: "CA", Country: "US"},
+	"seattle":       {City: "Seattle", Region: "WA", Country: "US"},
+	"redmond":       {City: "Redmond", Region: "WA", Country: "US"},
+	"boston":        {City: "Boston", Region: "MA", Country: "US"},
+	"chicago":       {City: "Chicago", Region: "IL", Country: "US"},
+	"austin":        {City: "Austin", Region: "TX", Country: "US"},
+	"dallas":        {City: "Dallas", Region: "TX", Country: "US"},
+	"atlanta":       {City: "Atlanta", Region: "GA", Country: "US"},
+	"denver":        {City: "Denver", Region: "CO", Country: "US"},
+	"dc":            {City: "Washington", Region: "DC", Country: "US"},
+	"washington dc": {City: "Washington", Region: "DC", Country: "US"},
+	"toronto":       {City: "Toronto", Region: "ON", Country: "CA"},
+	"waterloo":      {City: "Waterloo", Region: "ON", Country: "CA"},
+	"ottawa":        {City: "Ottawa", Region: "ON", Country: "CA"},
+	"montreal":      {City: "Montreal", Region: "QC", Country: "CA"},
+	"vancouver":     {City: "Vancouver", Region: "BC", Country: "CA"},
+	"london":        {City: "London", Country: "GB"},
+	"dublin":        {City: "Dublin", Country: "IE"},
+	"berlin":        {City: "Berlin", Country: "DE"},
+	"munich":        {City: "Munich", Country: "DE"},
+	"paris":         {City: "Paris", Country: "FR"},
+	"amsterdam":     {City: "Amsterdam", Country: "NL"},
+	"zurich":        {City: "Zurich", Country: "CH"},
+	"singapore":     {City: "Singapore", Country: "SG"},
+	"tokyo":         {City: "Tokyo", Country: "JP"},
+	"bangalore":     {City: "Bangalore", Country: "IN"},
+	"bengaluru":     {City: "Bangalore", Country: "IN"},
+	"sydney":        {City: "Sydney", Country: "AU"},
+}
+
+var (
+	workModeWords = regexp.MustCompile(`(?i)\b(fully remote|remote|hybrid|on[- ]?site|in[- ]office|in[- ]person|anywhere)\b`)
+	// Separators between parts of a location once work mode words are gone
+	locationSeparators = strings.NewReplacer("(", ",", ")", ",", "[", ",", "]", ",",
+		" - ", ",", " – ", ",", " — ", ",", "|", ",", "/", ",", ";", ",", "·", ",")
+)
+
+// ParseLocation turns strings like "NYC (Hybrid)", "Remote - US" or
+// "Waterloo, ON" into a Location. Text it can't place becomes the city;
+// with several locations ("NYC / SF") the first one wins.
+func ParseLocation(raw string) Location {
+	var loc Location
+	loc.WorkMode = parseWorkMode(raw)
+
+	text := locationSeparators.Replace(workModeWords.ReplaceAllString(raw, " "))
+	var parts []string
+	for _, part := range strings.Split(text, ",") {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	// A single part may be a known city, a region or a country on its own
+	if len(parts) == 1 {
+		key := strings.ToLower(parts[0])
+		if city, ok := knownCities[key]; ok {
+			city.WorkMode = loc.WorkMode
+			return city
+		}
+		if code, ok := countryCodes[key]; ok {
+			loc.Country = code
+			return loc
+		}
+		if region, country := parseRegion(key, ""); region != "" {
+			loc.Region, loc.Country = region, country
+			return loc
+		}
+		loc.City = truncate(parts[0], maxLocationPart)
+		return loc
+	}
+
+	// Otherwise read from the end: country, then region, then city
+	if len(parts) > 1 {
+		if code, ok := parseCountry(strings.ToLower(parts[len(parts)-1]), parts[0]); ok {
+			loc.Country = code
+			parts = parts[:len(parts)-1]
+		}
+	}
+	if len(parts) > 1 {
+		if region, country := parseRegion(strings.ToLower(parts[1]), loc.Country); region != "" {
+			loc.Region = region
+			if loc.Country == "" {
+				loc.Country = country
+			}
+		}
+	}
+	if len(parts) > 0 {
+		loc.City = truncate(parts[0], maxLocationPart)
+		if known, ok := knownCities[strings.ToLower(parts[0])]; ok && (loc.Country == "" || loc.Country == known.Country) {
+			loc.City = known.City
+			if loc.Region == "" {
+				loc.Region = known.Region
+			}
+			loc.Country = known.Country
+		}
+	}
+	return loc
+}
+
+func parseWorkMode(raw string) models.WorkMode {
+	words := strings.ToLower(strings.Join(workModeWords.FindAllString(raw, -1), " "))
+	switch {
+	case strings.Contains(words, "hybrid"):
+		return models.WorkModeHybrid
+	case strings.Contains(words, "remote"), strings.Contains(words, "anywhere"):
+		return models.WorkModeRemote
+	case words != "":
+		return models.WorkModeOnsite
+	default:
+		return ""
+	}
+}
+
+// parseCountry resolves a country name or code. Codes that are also a state
+// or province, like "IN" or "CA", are only the country when city is a known
+// city there, so "Bangalore, IN" is in India but "Springfield, IL" is in
+// Illinois.
+func parseCountry(key, city string) (string, bool) {
+	if code, ok := countryCodes[key]; ok {
+		return code, true
+	}
+	code := strings.ToUpper(key)
+	if !isoCountries[code] {
+		return "", false
+	}
+	if region, _ := parseRegion(key, ""); region == "" {
+		return code, true
+	}
+	known, ok := knownCities[strings.ToLower(city)]
+	return code, ok && known.Country == code
+}
+
+// parseRegion resolves a US state or Canadian province, by code or name, to
+// its code and country. country, if known, decides which list is searched
+// first, so "CA" is California unless the location is in Canada.
+func parseRegion(key, country string) (string, string) {
+	lists := []struct {
+		country string
+		regions map[string]string
+	}{{"US", usStates}, {"CA", caProvinces}}
+	if country == "CA" {
+		lists[0], lists[1] = lists[1], lists[0]
+	}
+
+	for _, list := range lists {
+		if country != "" && country != list.country {
+			continue
+		}
+		for code, name := range list.regions {
+			if key == code || key == name {
+				return strings.ToUpper(code), list.country
+			}
+		}
+	}
+	return "", ""
+}
+
+// ApplyLocation fills the application's structured location from its
+// Location string
+func ApplyLocation(app *models.Application) {
+	loc := ParseLocation(app.Location)
+	app.City = loc.City
+	app.Region = loc.Region
+	app.Country = loc.Country
+	app.WorkMode = loc.WorkMode
+}
+
+// HasStructuredLocation reports whether any structured location field is set
+func HasStructuredLocation(app *models.Application) bool {
+	return app.City != "" || app.Region != "" || app.Country != "" || app.WorkMode != ""
+}
+
+// BackfillLocations parses the location of applications saved before
+// structured locations existed. It runs at startup and only touches
+// applications without any structured location.
+func BackfillLocations() {
+	var apps []models.Application
+	if err := config.DB.Unscoped().
+		// The columns are NULL on rows saved before they were added
+		Where("location <> '' AND COALESCE(city, '') = '' AND COALESCE(region, '') = '' " +
+			"AND COALESCE(country, '') = '' AND COALESCE(work_mode, '') = ''").
+		Find(&apps).Error; err != nil {
+		log.Printf("Locations: failed to load applications to backfill: %v", err)
+		return
+	}
+
+	parsed := 0
+	for i := range apps {
+		app := &apps[i]
+		ApplyLocation(app)
+		if !HasStructuredLocation(app) {
+			continue
+		}
+		if err := config.DB.Unscoped().Model(app).Updates(locationColumns(app)).Error; err != nil {
+			log.Printf("Locations: failed to backfill application %d: %v", app.ID, err)
+			continue
+		}
+		parsed++
+	}
+	if parsed > 0 {
+		log.Printf("Locations: parsed the location of %d applications", parsed)
+	}
+}
+
+func locationColumns(app *models.Application) map[string]interface{} {
+	return map[string]interface{}{
+		"city":      app.City,
+		"region":    app.Region,
+		"country":   app.Country,
+		"work_mode": app.WorkMode,
+	}
+}
+
+// LocationFacets counts applications per work mode, country, region and city
+type LocationFacets struct {
+	WorkModes []FacetCount `json:"work_modes"`
+	Countries []FacetCount `json:"countries"`
+	Regions   []FacetCount `json:"regions"`
+	Cities    []FacetCount `json:"cities"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// BuildLocationFacets counts the applications matched by query, which must
+// be a query on applications
+func BuildLocationFacets(query *gorm.DB) (*LocationFacets, error) {
+	facets := &LocationFacets{}
+	for column, counts := range map[string]*[]FacetCount{
+		"work_mode": &facets.WorkModes,
+		"country":   &facets.Countries,
+		"region":    &facets.Regions,
+		"city":      &facets.Cities,
+	} {
+		*counts = []FacetCount{}
+		if err := query.Session(&gorm.Session{}).
+			Select(column + " AS value, COUNT(*) AS count").
+			Where(column + " <> ''").
+			Group(column).
+			Order("count DESC, value ASC").
+			Scan(counts).Error; err != nil {
+			return nil, err
+		}
+	}
+	return facets, nil
+}
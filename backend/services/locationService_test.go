@@ -0,0 +1,47 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+)
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Location
+	}{
+		{"", Location{}},
+		{"NYC (Hybrid)", Location{City: "New York", Region: "NY", Country: "US", WorkMode: models.WorkModeHybrid}},
+		{"Remote - US", Location{Country: "US", WorkMode: models.WorkModeRemote}},
+		{"Waterloo, ON", Location{City: "Waterloo", Region: "ON", Country: "CA"}},
+		{"San Jose, CA", Location{City: "San Jose", Region: "CA", Country: "US"}},
+		{"Toronto, CA", Location{City: "Toronto", Region: "ON", Country: "CA"}},
+		{"Springfield, IL", Location{City: "Springfield", Region: "IL", Country: "US"}},
+		{"Bangalore, IN", Location{City: "Bangalore", Country: "IN"}},
+		{"Pune, India", Location{City: "Pune", Country: "IN"}},
+		{"Lyon, FR", Location{City: "Lyon", Country: "FR"}},
+		{"Austin, Texas, USA", Location{City: "Austin", Region: "TX", Country: "US"}},
+		{"London, UK (On-site)", Location{City: "London", Country: "GB", WorkMode: models.WorkModeOnsite}},
+		{"NYC / SF", Location{City: "New York", Region: "NY", Country: "US"}},
+		{"Ontario", Location{Region: "ON", Country: "CA"}},
+		{"Somewhere", Location{City: "Somewhere"}},
+	}
+
+	for _, test := range tests {
+		if got := ParseLocation(test.raw); got != test.want {
+			t.Errorf("ParseLocation(%q) = %+v, want %+v", test.raw, got, test.want)
+		}
+	}
+}
+
+func TestParseLocationCutsLongCities(t *testing.T) {
+	raw := strings.Repeat("é", 150)
+	if got := ParseLocation(raw); got.City != strings.Repeat("é", maxLocationPart) {
+		t.Errorf("ParseLocation kept a city of %d characters", len([]rune(got.City)))
+	}
+	if got := ParseLocation(raw + ", Germany"); got.City != strings.Repeat("é", maxLocationPart) || got.Country != "DE" {
+		t.Errorf("ParseLocation = %+v, want a cut city in DE", got)
+	}
+}
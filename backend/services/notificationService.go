@@ -0,0 +1,227 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetNotificationSettings returns the user's saved settings, or the defaults
+// if they never changed them
+func GetNotificationSettings(db *gorm.DB, userID uint) (models.NotificationSettings, error) {
+	var settings models.NotificationSettings
+	result := db.Where("user_id = ?", userID).Limit(1).Find(&settings)
+	if result.Error != nil {
+		return settings, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.DefaultNotificationSettings(userID), nil
+	}
+	return settings, nil
+}
+
+// Notify delivers a notification over every channel the user enabled for
+// event. It runs inside tx, so nothing is delivered unless the change that
+// caused the notification commits.
+func Notify(tx *gorm.DB, userID uint, event models.NotificationEvent, title, body string, applicationID *uint) error {
+	return NotifyWithData(tx, userID, event, title, body, applicationID, nil)
+}
+
+// NotifyWithData is Notify with extra structured data for webhook payloads
+func NotifyWithData(tx *gorm.DB, userID uint, event models.NotificationEvent, title, body string, applicationID *uint, data map[string]interface{}) error {
+	settings, err := GetNotificationSettings(tx, userID)
+	if err != nil {
+		return err
+	}
+	channels := settings.Channels(event)
+
+	if channels.InApp {
+		notification := models.Notification{
+			UserID:        userID,
+			Event:         event,
+			Title:         title,
+			Body:          body,
+			ApplicationID: applicationID,
+		}
+		if err := tx.Create(&notification).Error; err != nil {
+			return err
+		}
+	}
+
+	if channels.Email {
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		html, err := renderTemplate("notification.html", map[string]string{
+			"Title":  title,
+			"Body":   body,
+			"AppURL": os.Getenv("FRONTEND_URL"),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Emails that would land during quiet hours wait in the outbox until they end
+		sendAt := time.Now()
+		if end, quiet := QuietHoursEnd(&settings, UserLocation(&user), sendAt); quiet {
+			sendAt = end
+		}
+		if err := EnqueueEmailAt(tx, user.Email, title+" - Internship Hub", html, sendAt); err != nil {
+			return err
+		}
+	}
+
+	if channels.Webhook {
+		payload := map[string]interface{}{
+			"title":          title,
+			"body":           body,
+			"application_id": applicationID,
+		}
+		for key, value := range data {
+			payload[key] = value
+		}
+		if err := EnqueueWebhookEvent(tx, userID, event.WebhookEventType(), payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QuietHoursEnd reports whether now falls within the user's quiet hours and,
+// if so, when they end
+func QuietHoursEnd(settings *models.NotificationSettings, loc *time.Location, now time.Time) (time.Time, bool) {
+	start, okStart := parseClock(settings.QuietHoursStart)
+	end, okEnd := parseClock(settings.QuietHoursEnd)
+	if !okStart || !okEnd || start == end {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	var quiet bool
+	if start < end {
+		quiet = minute >= start && minute < end
+	} else {
+		// Quiet hours span midnight, e.g. 22:00-07:00
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	endsAt := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, loc)
+	if !endsAt.After(local) {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+	return endsAt, true
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// ValidClock reports whether value is a valid "HH:MM" time or empty
+func ValidClock(value string) bool {
+	if value == "" {
+		return true
+	}
+	_, ok := parseClock(value)
+	return ok
+}
+
+// StartNotificationWorker periodically notifies users about reminders that
+// came due and interviews happening within the next 24 hours
+func StartNotificationWorker() {
+	interval := config.GetEnvDuration("NOTIFICATION_CHECK_INTERVAL", time.Minute)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := notifyDueReminders(time.Now()); err != nil {
+				log.Printf("Notifications: failed to process reminders: %v", err)
+			}
+			if err := notifyUpcomingInterviews(time.Now()); err != nil {
+				log.Printf("Notifications: failed to process interviews: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+func notifyDueReminders(now time.Time) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		var reminders []models.Reminder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Scopes(models.WithoutTrashedApplications).
+			Where("completed_at IS NULL AND notified_at IS NULL AND due_at <= ?", now).
+			Limit(100).Find(&reminders).Error; err != nil {
+			return err
+		}
+
+		for _, reminder := range reminders {
+			if err := Notify(tx, reminder.UserID, models.EventReminderDue,
+				"Reminder: "+reminder.Title, "This reminder is now due.", reminder.ApplicationID); err != nil {
+				return err
+			}
+			if err := tx.Model(&reminder).UpdateColumn("notified_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func notifyUpcomingInterviews(now time.Time) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		var interviews []models.Interview
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Application").
+			Scopes(models.WithoutTrashedApplications).
+			Where("notified_at IS NULL AND scheduled_at BETWEEN ? AND ?", now, now.Add(24*time.Hour)).
+			Limit(100).Find(&interviews).Error; err != nil {
+			return err
+		}
+
+		for _, interview := range interviews {
+			var user models.User
+			if err := tx.First(&user, interview.UserID).Error; err != nil {
+				return err
+			}
+
+			title := "Upcoming interview"
+			if interview.Application != nil {
+				title = fmt.Sprintf("Upcoming %s interview", interview.Application.Company)
+			}
+			when := interview.ScheduledAt.In(UserLocation(&user)).Format("Mon Jan 2, 3:04 PM MST")
+			body := fmt.Sprintf("Your interview is scheduled for %s.", when)
+			if interview.Kind != "" {
+				body = fmt.Sprintf("Your %s interview is scheduled for %s.", interview.Kind, when)
+			}
+
+			if err := Notify(tx, interview.UserID, models.EventInterviewUpcoming, title, body, &interview.ApplicationID); err != nil {
+				return err
+			}
+			if err := tx.Model(&interview).UpdateColumn("notified_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+)
+
+const weeksPerYear = 52
+
+// OfferComparison puts offers side by side, with pay converted to one
+// period and currency
+type OfferComparison struct {
+	Currency     string          `json:"currency"`
+	Period       string          `json:"period"`
+	HoursPerWeek float64         `json:"hours_per_week"`
+	Offers       []ComparedOffer `json:"offers"`
+	Warnings     []string        `json:"warnings"`
+}
+
+// ComparedOffer is one offer of a comparison. Converted amounts are nil
+// when the rate table has no rate for the offer's currency.
+type ComparedOffer struct {
+	Offer          models.Offer `json:"offer"`
+	Pay            *float64     `json:"pay"`                       // In the comparison's currency and period
+	Stipends       *float64     `json:"stipends"`                  // Housing and relocation together
+	Weeks          float64      `json:"weeks,omitempty"`           // From the start and end dates
+	EstimatedTotal *float64     `json:"estimated_total,omitempty"` // Pay for the whole internship plus stipends, needs dates
+	Rank           int          `json:"rank,omitempty"`            // By pay, 1 is the best paid
+}
+
+// IsPayPeriod reports whether period is a pay period offers can use
+func IsPayPeriod(period string) bool {
+	return period == models.PayHourly || period == models.PayMonthly || period == models.PayAnnual
+}
+
+// CompareOffers converts the offers' pay to period and currency using the
+// rate table and ranks them by it
+func CompareOffers(offers []models.Offer, rates config.OfferRates, currency, period string) OfferComparison {
+	comparison := OfferComparison{
+		Currency:     currency,
+		Period:       period,
+		HoursPerWeek: rates.HoursPerWeek,
+		Offers:       make([]ComparedOffer, 0, len(offers)),
+		Warnings:     []string{},
+	}
+
+	for _, offer := range offers {
+		compared := ComparedOffer{Offer: offer}
+		if offer.StartDate != nil && offer.EndDate != nil && offer.EndDate.After(*offer.StartDate) {
+			compared.Weeks = roundAmount(offer.EndDate.Sub(*offer.StartDate).Hours() / 24 / 7)
+		}
+
+		rate, ok := rates.Rates[offer.Currency]
+		if !ok {
+			comparison.Warnings = append(comparison.Warnings,
+				fmt.Sprintf("No exchange rate for %s, offer %d is not converted", offer.Currency, offer.ID))
+			comparison.Offers = append(comparison.Offers, compared)
+			continue
+		}
+		convert := func(amount float64) float64 {
+			return amount * rate / rates.Rates[currency]
+		}
+
+		annual := convert(annualPay(offer.PayAmount, offer.PayPeriod, rates.HoursPerWeek))
+		pay := roundAmount(payForPeriod(annual, period, rates.HoursPerWeek))
+		stipends := roundAmount(convert(offer.HousingStipend + offer.RelocationStipend))
+		compared.Pay = &pay
+		compared.Stipends = &stipends
+		if compared.Weeks > 0 {
+			total := roundAmount(annual/weeksPerYear*compared.Weeks + stipends)
+			compared.EstimatedTotal = &total
+		}
+		comparison.Offers = append(comparison.Offers, compared)
+	}
+
+	// Best paid first, offers that couldn't be converted last
+	sort.SliceStable(comparison.Offers, func(i, j int) bool {
+		a, b := comparison.Offers[i].Pay, comparison.Offers[j].Pay
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return *a > *b
+	})
+	for i := range comparison.Offers {
+		if comparison.Offers[i].Pay != nil {
+			comparison.Offers[i].Rank = i + 1
+		}
+	}
+
+	return comparison
+}
+
+func annualPay(amount float64, period string, hoursPerWeek float64) float64 {
+	switch period {
+	case models.PayHourly:
+		return amount * hoursPerWeek * weeksPerYear
+	case models.PayMonthly:
+		return amount * 12
+	default:
+		return amount
+	}
+}
+
+func payForPeriod(annual float64, period string, hoursPerWeek float64) float64 {
+	switch period {
+	case models.PayHourly:
+		return annual / weeksPerYear / hoursPerWeek
+	case models.PayMonthly:
+		return annual / 12
+	default:
+		return annual
+	}
+}
+
+// roundAmount rounds to cents
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OIDCClaims are the ID token claims used to sign users in
+type OIDCClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string      `json:"nonce"`
+	Email             string      `json:"email"`
+	EmailVerifiedRaw  interface{} `json:"email_verified"` // Some providers send "true" as a string
+	Name              string      `json:"name"`
+	PreferredUsername string      `json:"preferred_username"`
+}
+
+// EmailVerified reports whether the provider vouches for the email address
+func (c *OIDCClaims) EmailVerified() bool {
+	switch v := c.EmailVerifiedRaw.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	fetchedAt             time.Time
+}
+
+type jwksCache struct {
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+var (
+	oidcMu         sync.Mutex
+	discoveryCache = make(map[string]*oidcDiscovery)
+	keyCache       = make(map[string]*jwksCache)
+	oidcClient     = &http.Client{Timeout: 10 * time.Second}
+)
+
+// NewPKCE returns a PKCE code verifier and its S256 challenge
+func NewPKCE() (string, string) {
+	verifier := randomURLSafe(32)
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomURLSafe(n int) string {
+	bytes := make([]byte, n)
+	rand.Read(bytes)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+// NewOIDCState returns random values for the state and nonce parameters
+func NewOIDCState() (string, string) {
+	return randomURLSafe(24), randomURLSafe(24)
+}
+
+// OIDCAuthorizationURL builds the URL that starts the provider's
+// authorization code flow
+func OIDCAuthorizationURL(provider config.OIDCProvider, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := discover(provider.Issuer)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {provider.ClientID},
+		"redirect_uri":          {provider.RedirectURL},
+		"scope":                 {strings.Join(provider.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// ExchangeOIDCCode redeems an authorization code and returns the verified ID
+// token claims. nonce must match the one sent with the authorization request.
+func ExchangeOIDCCode(provider config.OIDCProvider, code, codeVerifier, nonce string) (*OIDCClaims, error) {
+	discovery, err := discover(provider.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {provider.RedirectURL},
+		"code_verifier": {codeVerifier},
+		"client_id":     {provider.ClientID},
+	}
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if provider.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(provider.ClientID), url.QueryEscape(provider.ClientSecret))
+	}
+
+	resp, err := oidcClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint responded with status %d: %s", resp.StatusCode, body)
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokens); err != nil || tokens.IDToken == "" {
+		return nil, errors.New("token response did not include an id_token")
+	}
+
+	return verifyIDToken(provider, discovery, tokens.IDToken, nonce)
+}
+
+func verifyIDToken(provider config.OIDCProvider, discovery *oidcDiscovery, rawToken, nonce string) (*OIDCClaims, error) {
+	claims := &OIDCClaims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return providerKey(discovery.JWKSURI, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(provider.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id_token: missing subject")
+	}
+	return claims, nil
+}
+
+// discover fetches and caches the provider's OpenID configuration
+func discover(issuer string) (*oidcDiscovery, error) {
+	oidcMu.Lock()
+	cached := discoveryCache[issuer]
+	oidcMu.Unlock()
+	if cached != nil && time.Since(cached.fetchedAt) < time.Hour {
+		return cached, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := getJSON(issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("OIDC discovery returned issuer %q, expected %q", discovery.Issuer, issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery document is missing endpoints")
+	}
+	discovery.fetchedAt = time.Now()
+
+	oidcMu.Lock()
+	discoveryCache[issuer] = &discovery
+	oidcMu.Unlock()
+	return &discovery, nil
+}
+
+// providerKey returns the signing key with the given kid, refetching the
+// JWKS if the key is unknown since providers rotate their keys
+func providerKey(jwksURI, kid string) (crypto.PublicKey, error) {
+	oidcMu.Lock()
+	cached := keyCache[jwksURI]
+	oidcMu.Unlock()
+
+	if cached != nil {
+		if key := lookupKey(cached.keys, kid); key != nil {
+			return key, nil
+		}
+		// Don't let tokens with made up kids hammer the provider
+		if time.Since(cached.fetchedAt) < time.Minute {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+	}
+
+	keys, err := fetchJWKS(jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	oidcMu.Lock()
+	keyCache[jwksURI] = &jwksCache{keys: keys, fetchedAt: time.Now()}
+	oidcMu.Unlock()
+
+	if key := lookupKey(keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func lookupKey(keys map[string]crypto.PublicKey, kid string) crypto.PublicKey {
+	if kid != "" {
+		return keys[kid]
+	}
+	// Tokens without a kid are only unambiguous if there is a single key
+	if len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return nil
+}
+
+func fetchJWKS(jwksURI string) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := getJSON(jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue // Skip key types we don't support
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func getJSON(url string, v interface{}) error {
+	resp, err := oidcClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
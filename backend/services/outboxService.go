@@ -0,0 +1,108 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueEmail writes an email to the outbox using tx, so it is only sent if
+// the surrounding transaction commits.
+func EnqueueEmail(tx *gorm.DB, recipient, subject, body string) error {
+	return EnqueueEmailWithHeaders(tx, recipient, subject, body, nil)
+}
+
+// EnqueueEmailWithHeaders is EnqueueEmail with extra message headers
+func EnqueueEmailWithHeaders(tx *gorm.DB, recipient, subject, body string, headers map[string]string) error {
+	return enqueueEmail(tx, recipient, subject, body, headers, time.Now())
+}
+
+// EnqueueEmailAt is EnqueueEmail for an email that must not go out before notBefore
+func EnqueueEmailAt(tx *gorm.DB, recipient, subject, body string, notBefore time.Time) error {
+	return enqueueEmail(tx, recipient, subject, body, nil, notBefore)
+}
+
+func enqueueEmail(tx *gorm.DB, recipient, subject, body string, headers map[string]string, notBefore time.Time) error {
+	return tx.Create(&models.OutboxEmail{
+		Recipient:     recipient,
+		Subject:       subject,
+		Body:          body,
+		Headers:       headers,
+		Status:        models.OutboxPending,
+		NextAttemptAt: notBefore,
+	}).Error
+}
+
+// StartOutboxWorker polls the outbox in the background and delivers due emails
+func StartOutboxWorker() {
+	interval := config.GetEnvDuration("OUTBOX_POLL_INTERVAL", 10*time.Second)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			processOutbox()
+			<-ticker.C
+		}
+	}()
+}
+
+func processOutbox() {
+	for {
+		processed, err := deliverNextEmail()
+		if err != nil {
+			log.Printf("Outbox: failed to process emails: %v", err)
+			return
+		}
+		if !processed {
+			return
+		}
+	}
+}
+
+// deliverNextEmail sends the oldest due email, if any. The row stays locked
+// while sending so that several backend replicas never send the same email.
+func deliverNextEmail() (bool, error) {
+	processed := false
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var email models.OutboxEmail
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(1).
+			Find(&email)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		processed = true
+
+		email.Attempts++
+		if err := SendEmail(email.Recipient, email.Subject, email.Body, email.Headers); err != nil {
+			email.LastError = err.Error()
+			if email.Attempts >= config.GetEnvInt("OUTBOX_MAX_ATTEMPTS", 8) {
+				email.Status = models.OutboxDead
+				log.Printf("Outbox: giving up on email %d to %s after %d attempts: %v", email.ID, email.Recipient, email.Attempts, err)
+			} else {
+				email.NextAttemptAt = time.Now().Add(backoffDelay(email.Attempts, 30*time.Second, time.Hour))
+			}
+		} else {
+			now := time.Now()
+			email.Status = models.OutboxSent
+			email.SentAt = &now
+			email.LastError = ""
+		}
+
+		return tx.Save(&email).Error
+	})
+
+	return processed, err
+}
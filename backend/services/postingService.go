@@ -0,0 +1,336 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Where a posting's details were found
+const (
+	PostingSourceJSONLD    = "json-ld"
+	PostingSourceOpenGraph = "opengraph"
+)
+
+// maxPostingDescription caps the characters of description kept from a posting
+const maxPostingDescription = 20000
+
+// Posting is the job posting details found in a page
+type Posting struct {
+	Source      string     `json:"source"`
+	Company     string     `json:"company"`
+	Position    string     `json:"position"`
+	Location    string     `json:"location"`
+	Description string     `json:"description"`
+	URL         string     `json:"url"`
+	Deadline    *time.Time `json:"deadline,omitempty"`
+}
+
+// pageMetadata is what ParsePostingHTML collects from the page
+type pageMetadata struct {
+	jsonLD    []string
+	meta      map[string]string // OpenGraph properties and named meta tags
+	title     string
+	canonical string
+}
+
+// ParsePostingHTML extracts a job posting from a page's HTML. It prefers a
+// schema.org JobPosting in JSON-LD and falls back to OpenGraph tags.
+// pageURL is where the page was captured, used when the page names no URL.
+func ParsePostingHTML(r io.Reader, pageURL string) (*Posting, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pageMetadata{meta: map[string]string{}}
+	collectMetadata(doc, page)
+
+	var posting *Posting
+	for _, script := range page.jsonLD {
+		if posting = parseJobPostingJSONLD(script); posting != nil {
+			break
+		}
+	}
+	if posting == nil {
+		posting = parseOpenGraph(page)
+	}
+	if posting == nil {
+		return nil, errors.New("no job posting found in the page")
+	}
+
+	if posting.URL == "" {
+		posting.URL = firstNonEmpty(page.meta["og:url"], page.canonical, pageURL)
+	}
+	if !IsHTTPURL(posting.URL) {
+		posting.URL = ""
+	}
+	posting.Description = strings.TrimSpace(truncate(posting.Description, maxPostingDescription))
+	return posting, nil
+}
+
+// truncate cuts value to at most max characters, never splitting one
+func truncate(value string, max int) string {
+	if runes := []rune(value); len(runes) > max {
+		return string(runes[:max])
+	}
+	return value
+}
+
+func collectMetadata(n *html.Node, page *pageMetadata) {
+	if n.Type == html.ElementNode {
+		switch n.DataAtom {
+		case atom.Script:
+			if strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") && n.FirstChild != nil {
+				page.jsonLD = append(page.jsonLD, n.FirstChild.Data)
+			}
+		case atom.Meta:
+			key := strings.ToLower(firstNonEmpty(attr(n, "property"), attr(n, "name")))
+			if key != "" && page.meta[key] == "" {
+				page.meta[key] = strings.TrimSpace(attr(n, "content"))
+			}
+		case atom.Title:
+			if page.title == "" {
+				page.title = strings.TrimSpace(textContent(n))
+			}
+		case atom.Link:
+			if strings.EqualFold(attr(n, "rel"), "canonical") {
+				page.canonical = attr(n, "href")
+			}
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		collectMetadata(child, page)
+	}
+}
+
+// parseJobPostingJSONLD finds a JobPosting in a JSON-LD script, which may
+// hold one object, a list of them or an @graph
+func parseJobPostingJSONLD(script string) *Posting {
+	var data interface{}
+	if err := json.Unmarshal([]byte(script), &data); err != nil {
+		return nil
+	}
+
+	job := findJobPosting(data)
+	if job == nil {
+		return nil
+	}
+
+	// Some sites escape the description's HTML once more
+	description := jsonString(job["description"])
+	if strings.Contains(description, "&lt;") {
+		description = html.UnescapeString(description)
+	}
+
+	return &Posting{
+		Source:      PostingSourceJSONLD,
+		Position:    html.UnescapeString(jsonString(job["title"])),
+		Company:     html.UnescapeString(organizationName(job["hiringOrganization"])),
+		Location:    jobLocation(job),
+		Description: HTMLToText(description),
+		URL:         jsonString(job["url"]),
+		Deadline:    parsePostingDate(jsonString(job["validThrough"])),
+	}
+}
+
+func findJobPosting(data interface{}) map[string]interface{} {
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if job := findJobPosting(item); job != nil {
+				return job
+			}
+		}
+	case map[string]interface{}:
+		if hasJSONLDType(v["@type"], "JobPosting") {
+			return v
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findJobPosting(graph)
+		}
+	}
+	return nil
+}
+
+func hasJSONLDType(value interface{}, want string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == want
+	case []interface{}:
+		for _, t := range v {
+			if s, ok := t.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func jsonString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return ""
+}
+
+// organizationName reads an Organization, given as an object or just a name
+func organizationName(value interface{}) string {
+	if org, ok := value.(map[string]interface{}); ok {
+		return jsonString(org["name"])
+	}
+	return jsonString(value)
+}
+
+// jobLocation formats the first place of a JobPosting as "City, Region,
+// Country", marking telecommute postings as remote
+func jobLocation(job map[string]interface{}) string {
+	place := job["jobLocation"]
+	if places, ok := place.([]interface{}); ok && len(places) > 0 {
+		place = places[0]
+	}
+
+	var parts []string
+	if p, ok := place.(map[string]interface{}); ok {
+		if address, ok := p["address"].(map[string]interface{}); ok {
+			for _, key := range []string{"addressLocality", "addressRegion", "addressCountry"} {
+				if value := firstNonEmpty(jsonString(address[key]), organizationName(address[key])); value != "" {
+					parts = append(parts, value)
+				}
+			}
+		} else if address := jsonString(p["address"]); address != "" {
+			parts = append(parts, address)
+		}
+	}
+
+	location := strings.Join(parts, ", ")
+	if strings.EqualFold(jsonString(job["jobLocationType"]), "TELECOMMUTE") {
+		if location == "" {
+			return "Remote"
+		}
+		return "Remote - " + location
+	}
+	return location
+}
+
+// parseOpenGraph builds a posting from OpenGraph tags. Titles like
+// "Software Engineer Intern at Acme" are split when the page doesn't name
+// the site.
+func parseOpenGraph(page *pageMetadata) *Posting {
+	title := firstNonEmpty(page.meta["og:title"], page.meta["twitter:title"], page.title)
+	if title == "" {
+		return nil
+	}
+
+	posting := &Posting{
+		Source:      PostingSourceOpenGraph,
+		Position:    title,
+		Company:     page.meta["og:site_name"],
+		Description: firstNonEmpty(page.meta["og:description"], page.meta["description"]),
+	}
+	if posting.Company == "" {
+		for _, sep := range []string{" at ", " - ", " | "} {
+			if position, company, ok := strings.Cut(title, sep); ok {
+				posting.Position, posting.Company = strings.TrimSpace(position), strings.TrimSpace(company)
+				break
+			}
+		}
+	}
+	return posting
+}
+
+// parsePostingDate reads schema.org dates, with or without a time
+func parsePostingDate(value string) *time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// HTMLToText turns an HTML fragment into plain text, one line per block
+func HTMLToText(fragment string) string {
+	if fragment == "" {
+		return ""
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return strings.TrimSpace(fragment)
+	}
+
+	var b strings.Builder
+	for _, n := range nodes {
+		writeText(&b, n)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func writeText(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		switch n.DataAtom {
+		case atom.Script, atom.Style:
+			return
+		case atom.Br:
+			b.WriteString("\n")
+			return
+		case atom.Li:
+			b.WriteString("\n- ")
+		case atom.P, atom.Div, atom.Ul, atom.Ol, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Tr:
+			b.WriteString("\n")
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		writeText(b, child)
+	}
+	if n.Type == html.ElementNode && n.DataAtom == atom.P {
+		b.WriteString("\n")
+	}
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	writeText(&b, n)
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// IsHTTPURL reports whether value is an absolute http or https URL
+func IsHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
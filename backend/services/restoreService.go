@@ -0,0 +1,515 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+)
+
+// Restore strategies decide what happens to data that is already there
+const (
+	RestoreSkip    = "skip"    // Keep existing applications, import the rest
+	RestoreUpdate  = "update"  // Overwrite existing applications with the archive's version
+	RestoreReplace = "replace" // Move all existing applications to the trash first
+)
+
+// maxRestoredResumeSize matches the upload limit for new applications
+const maxRestoredResumeSize = 5 << 20
+
+// maxArchiveJSONSize caps the JSON files read from an archive, which can
+// compress far smaller than they unpack
+const maxArchiveJSONSize = 100 << 20
+
+// RestoreReport counts what a restore did, per kind of record
+type RestoreReport struct {
+	Strategy string         `json:"strategy"`
+	Created  map[string]int `json:"created"`
+	Updated  map[string]int `json:"updated"`
+	Skipped  map[string]int `json:"skipped"`
+	Warnings []string       `json:"warnings"`
+}
+
+// ReadExportArchive reads and validates an archive produced by WriteUserExport
+func ReadExportArchive(archive *zip.Reader) (*ExportManifest, *ExportData, error) {
+	var manifest ExportManifest
+	if err := readArchiveJSON(archive, "manifest.json", &manifest); err != nil {
+		return nil, nil, err
+	}
+	if manifest.Format != ExportFormat {
+		return nil, nil, errors.New("not an Internship Hub export archive")
+	}
+	if manifest.SchemaVersion < 1 || manifest.SchemaVersion > ExportSchemaVersion {
+		return nil, nil, fmt.Errorf("unsupported export schema version %d (this server reads up to %d)",
+			manifest.SchemaVersion, ExportSchemaVersion)
+	}
+
+	var data ExportData
+	if err := readArchiveJSON(archive, "data.json", &data); err != nil {
+		return nil, nil, err
+	}
+	for _, app := range data.Applications {
+		if app.Status > models.StatusRejected {
+			return nil, nil, fmt.Errorf("application %d has an invalid status", app.ID)
+		}
+	}
+	return &manifest, &data, nil
+}
+
+func readArchiveJSON(archive *zip.Reader, name string, v interface{}) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("archive is missing %s", name)
+	}
+	defer file.Close()
+
+	limited := &io.LimitedReader{R: file, N: maxArchiveJSONSize + 1}
+	err = json.NewDecoder(limited).Decode(v)
+	if limited.N <= 0 {
+		return fmt.Errorf("%s is larger than %dMB", name, maxArchiveJSONSize>>20)
+	}
+	if err != nil {
+		return fmt.Errorf("%s is invalid: %v", name, err)
+	}
+	return nil
+}
+
+// RestoreUserData imports an export archive into the user's account. IDs
+// from the archive are remapped; applications match existing ones by
+// company, position and term. Everything happens in one transaction and
+// resume files written for a failed restore are removed again.
+func RestoreUserData(userID uint, archive *zip.Reader, data *ExportData, strategy string) (*RestoreReport, error) {
+	report := &RestoreReport{
+		Strategy: strategy,
+		Created:  map[string]int{},
+		Updated:  map[string]int{},
+		Skipped:  map[string]int{},
+		Warnings: []string{},
+	}
+
+	var written, replaced []string
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if strategy == RestoreReplace {
+			if err := trashUserApplications(tx, userID); err != nil {
+				return err
+			}
+		}
+
+		var existing []models.Application
+		if err := tx.Where("user_id = ?", userID).Find(&existing).Error; err != nil {
+			return err
+		}
+		byKey := make(map[string]*models.Application, len(existing))
+		for i := range existing {
+			byKey[applicationKey(&existing[i])] = &existing[i]
+		}
+
+		tags, err := restoreTags(tx, userID, data, report)
+		if err != nil {
+			return err
+		}
+		if err := restoreCompanies(tx, userID, data, report); err != nil {
+			return err
+		}
+
+		// Children are only imported for applications created by this restore,
+		// existing applications already have their own history
+		created := make(map[uint]uint)
+
+		for _, imported := range data.Applications {
+			app := imported.Application
+			oldID := app.ID
+			appTags := app.Tags
+			app.Tags = nil // Attached below, by name
+
+			match := byKey[applicationKey(&app)]
+			if match != nil && strategy == RestoreSkip {
+				report.Skipped["applications"]++
+				continue
+			}
+
+			resumeURL := ""
+			if imported.ResumeFile != "" {
+				url, err := restoreResume(archive, imported.ResumeFile, app.ResumeURL)
+				if err != nil {
+					report.Warnings = append(report.Warnings,
+						fmt.Sprintf("Resume for %s - %s was not restored: %v", app.Company, app.Position, err))
+				} else {
+					resumeURL = url
+					written = append(written, "."+url)
+				}
+			}
+
+			if match != nil {
+				oldResume, previousStatus := match.ResumeURL, match.Status
+				match.Status = app.Status
+				match.Location = app.Location
+				match.City, match.Region, match.Country, match.WorkMode = app.City, app.Region, app.Country, app.WorkMode
+				if !HasStructuredLocation(match) {
+					ApplyLocation(match)
+				}
+				match.AppliedDate = app.AppliedDate
+				match.Note = app.Note
+				match.PostingURL, match.Description, match.Deadline = app.PostingURL, app.Description, app.Deadline
+				if resumeURL != "" {
+					match.ResumeURL = resumeURL
+				}
+				if err := tx.Save(match).Error; err != nil {
+					return err
+				}
+				if err := RecordStatusChange(tx, match, previousStatus); err != nil {
+					return err
+				}
+				if err := attachRestoredTags(tx, match, appTags, tags); err != nil {
+					return err
+				}
+				if err := PublishApplicationEvent(tx, models.EventApplicationUpdated, match); err != nil {
+					return err
+				}
+				if resumeURL != "" && oldResume != "" {
+					replaced = append(replaced, "."+oldResume)
+				}
+				report.Updated["applications"]++
+				continue
+			}
+
+			app.ID = 0
+			app.UserID = userID
+			app.ResumeURL = resumeURL
+			if !HasStructuredLocation(&app) {
+				ApplyLocation(&app) // Archives from before structured locations
+			}
+			if err := LinkTerm(tx, &app); err != nil {
+				return err
+			}
+			if _, _, err := LinkCompany(tx, &app); err != nil {
+				return err
+			}
+			if err := tx.Create(&app).Error; err != nil {
+				return err
+			}
+			if err := attachRestoredTags(tx, &app, appTags, tags); err != nil {
+				return err
+			}
+			if err := PublishApplicationEvent(tx, models.EventApplicationCreated, &app); err != nil {
+				return err
+			}
+			created[oldID] = app.ID
+			byKey[applicationKey(&app)] = &app
+			report.Created["applications"]++
+		}
+
+		for _, change := range data.StatusHistory {
+			newID, ok := created[change.ApplicationID]
+			if !ok {
+				report.Skipped["status_history"]++
+				continue
+			}
+			change.ID = 0
+			change.ApplicationID = newID
+			change.UserID = userID
+			if err := tx.Create(&change).Error; err != nil {
+				return err
+			}
+			report.Created["status_history"]++
+		}
+
+		for _, interview := range data.Interviews {
+			newID, ok := created[interview.ApplicationID]
+			if !ok {
+				report.Skipped["interviews"]++
+				continue
+			}
+			interview.ID = 0
+			interview.ApplicationID = newID
+			interview.UserID = userID
+			interview.Application = nil
+			if err := tx.Create(&interview).Error; err != nil {
+				return err
+			}
+			report.Created["interviews"]++
+		}
+
+		for _, reminder := range data.Reminders {
+			if reminder.ApplicationID != nil {
+				newID, ok := created[*reminder.ApplicationID]
+				if !ok {
+					report.Skipped["reminders"]++
+					continue
+				}
+				reminder.ApplicationID = &newID
+			} else {
+				// Standalone reminders match on title and due date
+				var count int64
+				tx.Model(&models.Reminder{}).
+					Where("user_id = ? AND application_id IS NULL AND title = ? AND due_at = ?", userID, reminder.Title, reminder.DueAt).
+					Count(&count)
+				if count > 0 {
+					report.Skipped["reminders"]++
+					continue
+				}
+			}
+			reminder.ID = 0
+			reminder.UserID = userID
+			reminder.Application = nil
+			if err := tx.Create(&reminder).Error; err != nil {
+				return err
+			}
+			report.Created["reminders"]++
+		}
+
+		for _, offer := range data.Offers {
+			newID, ok := created[offer.ApplicationID]
+			if !ok {
+				report.Skipped["offers"]++
+				continue
+			}
+			offer.ID = 0
+			offer.ApplicationID = newID
+			offer.UserID = userID
+			offer.Application = nil
+			if err := tx.Create(&offer).Error; err != nil {
+				return err
+			}
+			report.Created["offers"]++
+		}
+
+		if err := restoreWishlist(tx, userID, data, report); err != nil {
+			return err
+		}
+
+		return restoreSettings(tx, userID, data, strategy, report)
+	})
+	if err != nil {
+		RemoveFiles(written)
+		return nil, err
+	}
+
+	RemoveFiles(replaced)
+	return report, nil
+}
+
+// restoreTags finds or creates the user's tag for each tag in the archive,
+// keyed by lowercase name
+func restoreTags(tx *gorm.DB, userID uint, data *ExportData, report *RestoreReport) (map[string]*models.Tag, error) {
+	var existing []models.Tag
+	if err := tx.Where("user_id = ?", userID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	tags := make(map[string]*models.Tag, len(existing))
+	for i := range existing {
+		tags[strings.ToLower(existing[i].Name)] = &existing[i]
+	}
+
+	for _, imported := range data.Tags {
+		key := strings.ToLower(strings.TrimSpace(imported.Name))
+		if key == "" {
+			continue
+		}
+		if tags[key] != nil {
+			report.Skipped["tags"]++
+			continue
+		}
+		tag := models.Tag{UserID: userID, Name: strings.TrimSpace(imported.Name), Color: imported.Color}
+		if err := tx.Create(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags[key] = &tag
+		report.Created["tags"]++
+	}
+	return tags, nil
+}
+
+// restoreCompanies creates the archive's companies that don't resolve to
+// one of the user's companies yet. Restored applications link to them by
+// name, like new applications do.
+func restoreCompanies(tx *gorm.DB, userID uint, data *ExportData, report *RestoreReport) error {
+	for _, imported := range data.Companies {
+		var existing *models.Company
+		for _, name := range CompanyNames(&imported) {
+			company, err := FindCompany(tx, userID, name)
+			if err != nil {
+				return err
+			}
+			if company != nil {
+				existing = company
+				break
+			}
+		}
+		if existing != nil || NormalizeCompanyName(imported.Name) == "" {
+			report.Skipped["companies"]++
+			continue
+		}
+
+		company := models.Company{
+			UserID:         userID,
+			Name:           strings.TrimSpace(imported.Name),
+			NormalizedName: NormalizeCompanyName(imported.Name),
+			Aliases:        []string{},
+			Website:        imported.Website,
+			Industry:       imported.Industry,
+			Headquarters:   imported.Headquarters,
+		}
+		for _, alias := range imported.Aliases {
+			AddCompanyAlias(&company, alias)
+		}
+		if err := tx.Create(&company).Error; err != nil {
+			return err
+		}
+		report.Created["companies"]++
+	}
+	return nil
+}
+
+// restoreWishlist imports the wishlist items that aren't on the user's
+// wishlist yet, matching by company, position and term
+func restoreWishlist(tx *gorm.DB, userID uint, data *ExportData, report *RestoreReport) error {
+	var existing []models.WishlistItem
+	if err := tx.Where("user_id = ?", userID).Find(&existing).Error; err != nil {
+		return err
+	}
+	keys := make(map[string]bool, len(existing))
+	for i := range existing {
+		keys[wishlistKey(&existing[i])] = true
+	}
+
+	for _, item := range data.Wishlist {
+		key := wishlistKey(&item)
+		if keys[key] {
+			report.Skipped["wishlist"]++
+			continue
+		}
+		item.ID = 0
+		item.UserID = userID
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		keys[key] = true
+		report.Created["wishlist"]++
+	}
+	return nil
+}
+
+func wishlistKey(item *models.WishlistItem) string {
+	return applicationKey(&models.Application{Company: item.Company, Position: item.Position, Term: item.Term})
+}
+
+func attachRestoredTags(tx *gorm.DB, app *models.Application, imported []models.Tag, tags map[string]*models.Tag) error {
+	var attach []*models.Tag
+	for _, tag := range imported {
+		if t := tags[strings.ToLower(strings.TrimSpace(tag.Name))]; t != nil {
+			attach = append(attach, t)
+		}
+	}
+	if len(attach) == 0 {
+		return nil
+	}
+	return tx.Model(app).Association("Tags").Append(attach)
+}
+
+// restoreSettings restores the time zone and notification settings, unless
+// existing ones should be kept
+func restoreSettings(tx *gorm.DB, userID uint, data *ExportData, strategy string, report *RestoreReport) error {
+	if strategy == RestoreSkip {
+		report.Skipped["settings"]++
+		return nil
+	}
+
+	if _, err := time.LoadLocation(data.Profile.Timezone); err == nil && data.Profile.Timezone != "" {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("timezone", data.Profile.Timezone).Error; err != nil {
+			return err
+		}
+	}
+
+	if data.NotificationSettings.UserID == 0 {
+		return nil
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&models.NotificationSettings{}).Error; err != nil {
+		return err
+	}
+	settings := data.NotificationSettings
+	settings.ID = 0
+	settings.UserID = userID
+	if err := tx.Create(&settings).Error; err != nil {
+		return err
+	}
+	report.Updated["settings"]++
+	return nil
+}
+
+// trashUserApplications moves all of the user's applications to the trash,
+// so a replace can still be undone
+func trashUserApplications(tx *gorm.DB, userID uint) error {
+	var apps []models.Application
+	if err := tx.Where("user_id = ?", userID).Find(&apps).Error; err != nil {
+		return err
+	}
+
+	for i := range apps {
+		if err := tx.Delete(&apps[i]).Error; err != nil {
+			return err
+		}
+		if err := PublishApplicationEvent(tx, models.EventApplicationDeleted, &apps[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applicationKey identifies an application by company, position and term,
+// compared the way companies and terms are normalized
+func applicationKey(app *models.Application) string {
+	term := strings.ToLower(strings.TrimSpace(app.Term))
+	if season, year, ok := ParseTerm(app.Term); ok {
+		term = TermName(season, year)
+	}
+	return NormalizeCompanyName(app.Company) + "\x00" +
+		strings.ToLower(strings.TrimSpace(app.Position)) + "\x00" + term
+}
+
+// restoreResume copies a resume out of the archive into uploads and returns
+// its new URL
+func restoreResume(archive *zip.Reader, name, originalURL string) (string, error) {
+	if !strings.HasPrefix(name, "resumes/") {
+		return "", errors.New("resume is outside the resumes folder")
+	}
+	file, err := archive.Open(name)
+	if err != nil {
+		return "", errors.New("file is missing from the archive")
+	}
+	defer file.Close()
+
+	base := path.Base(originalURL)
+	if base == "." || base == "/" {
+		base = path.Base(name)
+	}
+	filename := fmt.Sprintf("%d_%s", time.Now().UnixNano(), base)
+	uploadPath := "./uploads/" + filename
+
+	out, err := os.Create(uploadPath)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	n, err := io.Copy(out, io.LimitReader(file, maxRestoredResumeSize+1))
+	if err == nil && n > maxRestoredResumeSize {
+		err = errors.New("file is larger than 5MB")
+	}
+	if err != nil {
+		out.Close()
+		os.Remove(uploadPath)
+		return "", err
+	}
+	return "/uploads/" + filename, nil
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+)
+
+// SignValue returns an HMAC signature binding value to a purpose, so a
+// signature issued for one kind of link can't be replayed for another
+func SignValue(purpose, value string) string {
+	mac := hmac.New(sha256.New, []byte(config.GetLinkSigningSecret()))
+	mac.Write([]byte(purpose + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignedValue checks a signature produced by SignValue
+func VerifySignedValue(purpose, value, signature string) bool {
+	return hmac.Equal([]byte(SignValue(purpose, value)), []byte(signature))
+}
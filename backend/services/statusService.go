@@ -0,0 +1,83 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+)
+
+// RecordStatusChange adds an entry to the application's status history and
+// notifies the user if its status differs from the previous one
+func RecordStatusChange(tx *gorm.DB, app *models.Application, previous models.ApplicationStatus) error {
+	return recordStatusChange(tx, app, previous, false)
+}
+
+func recordStatusChange(tx *gorm.DB, app *models.Application, previous models.ApplicationStatus, automated bool) error {
+	if app.Status == previous {
+		return nil
+	}
+
+	change := models.ApplicationStatusChange{
+		ApplicationID: app.ID,
+		UserID:        app.UserID,
+		FromStatus:    previous,
+		ToStatus:      app.Status,
+		Automated:     automated,
+	}
+	if err := tx.Create(&change).Error; err != nil {
+		return err
+	}
+
+	return NotifyWithData(tx, app.UserID, models.EventStatusChanged,
+		fmt.Sprintf("%s: %s", app.Company, app.Status),
+		fmt.Sprintf("Your %s application at %s moved from %s to %s.", app.Position, app.Company, previous, app.Status),
+		&app.ID,
+		map[string]interface{}{
+			"from_status": previous.String(),
+			"to_status":   app.Status.String(),
+			"application": app,
+		})
+}
+
+// SetApplicationStatus moves an application to status as part of tx,
+// recording the change and publishing it like an update made in the app
+func SetApplicationStatus(tx *gorm.DB, app *models.Application, status models.ApplicationStatus) error {
+	return setApplicationStatus(tx, app, status, false)
+}
+
+// setApplicationStatus is SetApplicationStatus, with automated set for
+// changes made by automation rules
+func setApplicationStatus(tx *gorm.DB, app *models.Application, status models.ApplicationStatus, automated bool) error {
+	previous := app.Status
+	if status == previous {
+		return nil
+	}
+	if err := tx.Model(app).Update("status", status).Error; err != nil {
+		return err
+	}
+	app.Status = status
+	if err := recordStatusChange(tx, app, previous, automated); err != nil {
+		return err
+	}
+	return PublishApplicationEvent(tx, models.EventApplicationUpdated, app)
+}
+
+// SetArchived archives or unarchives one application as part of tx
+func SetArchived(tx *gorm.DB, app *models.Application, archived bool) error {
+	app.Archived = archived
+	app.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		app.ArchivedAt = &now
+	}
+
+	if err := tx.Model(app).Updates(map[string]interface{}{
+		"archived":    app.Archived,
+		"archived_at": app.ArchivedAt,
+	}).Error; err != nil {
+		return err
+	}
+	return PublishApplicationEvent(tx, models.EventApplicationUpdated, app)
+}
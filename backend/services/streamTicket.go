@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+)
+
+const (
+	streamTicketPurpose = "event-stream"
+
+	// StreamTicketLifetime is how long a ticket can be used to open the
+	// event stream. It ends up in access logs, so it is kept short.
+	StreamTicketLifetime = time.Minute
+)
+
+// NewStreamTicket returns a ticket that opens the user's event stream
+// without putting their session or access token in the URL. It is tied to
+// the credential it was issued for: signing out, or deleting the access
+// token, invalidates it.
+func NewStreamTicket(user *models.User, token *models.PersonalAccessToken) (string, time.Time) {
+	credential := fmt.Sprintf("s%d", user.SessionVersion)
+	if token != nil {
+		credential = fmt.Sprintf("t%d", token.ID)
+	}
+	expiresAt := time.Now().Add(StreamTicketLifetime)
+
+	value := fmt.Sprintf("%d.%s.%d", user.ID, credential, expiresAt.Unix())
+	return value + "." + SignValue(streamTicketPurpose, value), expiresAt
+}
+
+// CheckStreamTicket returns the user a stream ticket was issued to
+func CheckStreamTicket(db *gorm.DB, ticket string) (*models.User, error) {
+	invalid := errors.New("invalid or expired ticket")
+
+	dot := strings.LastIndex(ticket, ".")
+	if dot < 0 || !VerifySignedValue(streamTicketPurpose, ticket[:dot], ticket[dot+1:]) {
+		return nil, invalid
+	}
+	parts := strings.Split(ticket[:dot], ".")
+	if len(parts) != 3 || len(parts[1]) < 2 {
+		return nil, invalid
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return nil, invalid
+	}
+
+	var user models.User
+	if err := db.Where("id = ?", parts[0]).First(&user).Error; err != nil {
+		return nil, invalid
+	}
+
+	id := parts[1][1:]
+	switch parts[1][0] {
+	case 's':
+		if id != strconv.FormatUint(uint64(user.SessionVersion), 10) {
+			return nil, invalid
+		}
+	case 't':
+		var token models.PersonalAccessToken
+		if err := db.Where("id = ? AND user_id = ?", id, user.ID).First(&token).Error; err != nil {
+			return nil, invalid
+		}
+		if token.ExpiresAt != nil && time.Now().After(*token.ExpiresAt) {
+			return nil, invalid
+		}
+	default:
+		return nil, invalid
+	}
+	return &user, nil
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"time"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var emailTemplates = template.Must(
+	template.New("").Funcs(template.FuncMap{
+		// date formats t in the recipient's time zone
+		"date": func(t time.Time, loc *time.Location) string {
+			return t.In(loc).Format("Mon Jan 2, 3:04 PM")
+		},
+		"day": func(t time.Time, loc *time.Location) string {
+			return t.In(loc).Format("Jan 2")
+		},
+	}).ParseFS(templateFS, "templates/*.html"),
+)
+
+// renderTemplate executes one of the embedded email templates
+func renderTemplate(name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := emailTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
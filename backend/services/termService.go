@@ -0,0 +1,294 @@
+package services
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Season names and abbreviations. A lone "S" means summer, as in "S25";
+// spring is "Sp".
+var seasonWords = map[string]string{
+	"winter": models.SeasonWinter, "w": models.SeasonWinter, "wi": models.SeasonWinter,
+	"spring": models.SeasonSpring, "sp": models.SeasonSpring,
+	"summer": models.SeasonSummer, "s": models.SeasonSummer, "su": models.SeasonSummer,
+	"fall": models.SeasonFall, "autumn": models.SeasonFall, "f": models.SeasonFall, "fa": models.SeasonFall,
+}
+
+// Months give the season of co-op style terms like "Jan-Apr 2026", by the
+// month the term starts
+var monthSeasons = map[string]string{
+	"jan": models.SeasonWinter, "january": models.SeasonWinter,
+	"feb": models.SeasonWinter, "february": models.SeasonWinter,
+	"mar": models.SeasonSpring, "march": models.SeasonSpring,
+	"apr": models.SeasonSpring, "april": models.SeasonSpring,
+	"may": models.SeasonSummer, "jun": models.SeasonSummer, "june": models.SeasonSummer,
+	"jul": models.SeasonSummer, "july": models.SeasonSummer,
+	"aug": models.SeasonSummer, "august": models.SeasonSummer,
+	"sep": models.SeasonFall, "sept": models.SeasonFall, "september": models.SeasonFall,
+	"oct": models.SeasonFall, "october": models.SeasonFall,
+	"nov": models.SeasonFall, "november": models.SeasonFall,
+	"dec": models.SeasonFall, "december": models.SeasonFall,
+}
+
+// ParseTerm reads the season and year from terms like "Summer 2025",
+// "summer '25", "S25", "2025 Fall" or "Jan-Apr 2026". ok is false unless
+// both were found.
+func ParseTerm(raw string) (season string, year int, ok bool) {
+	var shortYear int
+	prev := ""
+	for _, word := range termWords(raw) {
+		if word[0] >= '0' && word[0] <= '9' {
+			if y := parseTermYear(word, prev); y != 0 {
+				if len(word) == 4 && year == 0 {
+					year = y
+				} else if len(word) == 2 && shortYear == 0 {
+					shortYear = y
+				}
+			}
+		} else if season == "" {
+			if s, found := seasonWords[word]; found {
+				season = s
+			} else if s, found := monthSeasons[word]; found {
+				season = s
+			}
+		}
+		prev = word
+	}
+	// A four digit year wins over a two digit one
+	if year == 0 {
+		year = shortYear
+	}
+	return season, year, season != "" && year != 0
+}
+
+// termWords lowercases raw and splits it into words, also between letters
+// and digits so "S25" reads as "s" and "25". Apostrophes are words of their
+// own, so "'25" reads as "'" and "25".
+func termWords(raw string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	for _, r := range strings.ToLower(raw) {
+		switch {
+		case r == '\'' || r == '’':
+			flush()
+			words = append(words, "'")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if len(word) > 0 && unicode.IsDigit(word[len(word)-1]) != unicode.IsDigit(r) {
+				flush()
+			}
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// parseTermYear accepts four digit years, and two digit ones in this
+// century right after an apostrophe, a season or a month ("'25", "S25",
+// "Apr 26"), so numbers like "12-week" aren't taken for years
+func parseTermYear(word, prev string) int {
+	n, err := strconv.Atoi(word)
+	if err != nil {
+		return 0
+	}
+	switch {
+	case len(word) == 4 && n >= 1990 && n <= 2100:
+		return n
+	case len(word) == 2 && (prev == "'" || seasonWords[prev] != "" || monthSeasons[prev] != ""):
+		return 2000 + n
+	default:
+		return 0
+	}
+}
+
+// TermName is the normalized name of a term, e.g. "Summer 2025"
+func TermName(season string, year int) string {
+	return season + " " + strconv.Itoa(year)
+}
+
+// FindOrCreateTerm returns the term for season and year, creating it if no
+// application has used it yet
+func FindOrCreateTerm(db *gorm.DB, season string, year int) (*models.Term, error) {
+	term := models.Term{Season: season, Year: year, Name: TermName(season, year)}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&term).Error; err != nil {
+		return nil, err
+	}
+	if term.ID == 0 {
+		if err := db.Where("season = ? AND year = ?", season, year).First(&term).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &term, nil
+}
+
+// LinkTerm points the application at the term its Term parses to and
+// switches Term to the normalized name. Terms that can't be parsed are kept
+// as entered, without a term.
+func LinkTerm(tx *gorm.DB, app *models.Application) error {
+	app.TermID = nil
+	season, year, ok := ParseTerm(app.Term)
+	if !ok {
+		app.Term = strings.TrimSpace(app.Term)
+		return nil
+	}
+
+	term, err := FindOrCreateTerm(tx, season, year)
+	if err != nil {
+		return err
+	}
+	app.TermID = &term.ID
+	app.Term = term.Name
+	return nil
+}
+
+// TermBefore reports whether term a comes before term b in the calendar
+func TermBefore(a, b *models.Term) bool {
+	if a.Year != b.Year {
+		return a.Year < b.Year
+	}
+	return seasonIndex(a.Season) < seasonIndex(b.Season)
+}
+
+func seasonIndex(season string) int {
+	for i, s := range models.Seasons {
+		if s == season {
+			return i
+		}
+	}
+	return len(models.Seasons)
+}
+
+// BackfillTerms links applications saved before terms existed. It runs at
+// startup and only touches applications without a term.
+func BackfillTerms() {
+	var apps []models.Application
+	if err := config.DB.Unscoped().
+		Where("term_id IS NULL AND term <> ''").
+		Find(&apps).Error; err != nil {
+		log.Printf("Terms: failed to load applications to backfill: %v", err)
+		return
+	}
+
+	linked := 0
+	for i := range apps {
+		app := &apps[i]
+		if err := LinkTerm(config.DB, app); err != nil {
+			log.Printf("Terms: failed to backfill application %d: %v", app.ID, err)
+			continue
+		}
+		if app.TermID == nil {
+			continue
+		}
+		if err := config.DB.Unscoped().Model(app).Updates(map[string]interface{}{
+			"term_id": app.TermID,
+			"term":    app.Term,
+		}).Error; err != nil {
+			log.Printf("Terms: failed to backfill application %d: %v", app.ID, err)
+			continue
+		}
+		linked++
+	}
+	if linked > 0 {
+		log.Printf("Terms: linked %d applications to terms", linked)
+	}
+}
+
+// TermGroup is the applications of one term. Terms that couldn't be parsed
+// are grouped by name and have no Term.
+type TermGroup struct {
+	Term         *models.Term         `json:"term"`
+	Name         string               `json:"name"`
+	Active       bool                 `json:"active"` // The user's active term
+	Stats        OutcomeStats         `json:"stats"`
+	Applications []models.Application `json:"applications,omitempty"`
+}
+
+// GroupByTerm groups applications by normalized term, newest term first,
+// followed by the terms that couldn't be parsed. Applications without a
+// term are left out. withApplications includes the applications
+// themselves, otherwise only their stats.
+func GroupByTerm(db *gorm.DB, apps []models.Application, activeTermID *uint, withApplications bool) ([]TermGroup, error) {
+	var termIDs []uint
+	for _, app := range apps {
+		if app.TermID != nil {
+			termIDs = append(termIDs, *app.TermID)
+		}
+	}
+	terms := make(map[uint]*models.Term)
+	if len(termIDs) > 0 {
+		var found []models.Term
+		if err := db.Where("id IN ?", termIDs).Find(&found).Error; err != nil {
+			return nil, err
+		}
+		for i := range found {
+			terms[found[i].ID] = &found[i]
+		}
+	}
+
+	byKey := make(map[string]*TermGroup)
+	var order []string
+	for i := range apps {
+		app := &apps[i]
+		var key string
+		var term *models.Term
+		switch {
+		case app.TermID != nil && terms[*app.TermID] != nil:
+			term = terms[*app.TermID]
+			key = strconv.FormatUint(uint64(term.ID), 10)
+		case strings.TrimSpace(app.Term) != "":
+			key = "name:" + strings.ToLower(strings.TrimSpace(app.Term))
+		default:
+			continue
+		}
+
+		group := byKey[key]
+		if group == nil {
+			group = &TermGroup{Term: term, Name: strings.TrimSpace(app.Term), Stats: newOutcomeStats()}
+			if term != nil {
+				group.Name = term.Name
+				group.Active = activeTermID != nil && *activeTermID == term.ID
+			}
+			byKey[key] = group
+			order = append(order, key)
+		}
+		group.Stats.add(app)
+		if withApplications {
+			group.Applications = append(group.Applications, *app)
+		}
+	}
+
+	groups := make([]TermGroup, 0, len(order))
+	for _, key := range order {
+		byKey[key].Stats.computeRates()
+		groups = append(groups, *byKey[key])
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].Term, groups[j].Term
+		switch {
+		case a != nil && b != nil:
+			return TermBefore(b, a)
+		case a != nil || b != nil:
+			return a != nil
+		default:
+			return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+		}
+	})
+	return groups, nil
+}
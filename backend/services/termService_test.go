@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+)
+
+func TestParseTerm(t *testing.T) {
+	tests := []struct {
+		raw    string
+		season string
+		year   int
+		ok     bool
+	}{
+		{"Summer 2025", models.SeasonSummer, 2025, true},
+		{"summer '25", models.SeasonSummer, 2025, true},
+		{"Summer ’25", models.SeasonSummer, 2025, true},
+		{"S25", models.SeasonSummer, 2025, true},
+		{"Sp26", models.SeasonSpring, 2026, true},
+		{"Summer 25", models.SeasonSummer, 2025, true},
+		{"2025 Fall", models.SeasonFall, 2025, true},
+		{"Jan-Apr 2026", models.SeasonWinter, 2026, true},
+		{"Jan-Apr 26", models.SeasonWinter, 2026, true},
+		{"12-week Summer 2025", models.SeasonSummer, 2025, true},
+		{"Summer '25 (12 weeks)", models.SeasonSummer, 2025, true},
+		{"12 week summer", models.SeasonSummer, 0, false},
+		{"Fall", models.SeasonFall, 0, false},
+		{"2025", "", 2025, false},
+		{"", "", 0, false},
+	}
+
+	for _, test := range tests {
+		season, year, ok := ParseTerm(test.raw)
+		if season != test.season || year != test.year || ok != test.ok {
+			t.Errorf("ParseTerm(%q) = %q, %d, %v, want %q, %d, %v",
+				test.raw, season, year, ok, test.season, test.year, test.ok)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TrashRetention is how long deleted applications stay in the trash
+func TrashRetention() time.Duration {
+	return config.GetEnvDuration("TRASH_RETENTION", 30*24*time.Hour)
+}
+
+// PurgeApplication permanently deletes an application together with
+// everything that hangs off it, as part of tx. It returns the resume file to
+// remove once tx has committed.
+func PurgeApplication(tx *gorm.DB, app *models.Application) ([]string, error) {
+	// Emails stay in the user's inbox, without the application
+	if err := tx.Model(&models.InboundEmail{}).Where("application_id = ?", app.ID).
+		Update("application_id", nil).Error; err != nil {
+		return nil, err
+	}
+
+	children := []interface{}{
+		&models.ApplicationStatusChange{},
+		&models.Interview{},
+		&models.Reminder{},
+		&models.Offer{},
+		&models.AutomationExecution{},
+	}
+	for _, child := range children {
+		if err := tx.Where("application_id = ?", app.ID).Delete(child).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Exec("DELETE FROM application_tags WHERE application_id = ?", app.ID).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Unscoped().Delete(app).Error; err != nil {
+		return nil, err
+	}
+	if app.ResumeURL == "" {
+		return nil, nil
+	}
+	return []string{"." + app.ResumeURL}, nil
+}
+
+// StartTrashPurgeWorker permanently deletes applications that have been in
+// the trash for longer than TRASH_RETENTION, along with their resume files
+func StartTrashPurgeWorker() {
+	interval := config.GetEnvDuration("TRASH_PURGE_INTERVAL", time.Hour)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for {
+				purged, err := purgeExpiredTrash()
+				if err != nil {
+					log.Printf("Trash: failed to purge applications: %v", err)
+				}
+				if err != nil || purged == 0 {
+					break
+				}
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// purgeExpiredTrash purges a batch of expired applications and returns how
+// many it purged
+func purgeExpiredTrash() (int, error) {
+	var files []string
+	var apps []models.Application
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", time.Now().Add(-TrashRetention())).
+			Limit(100).
+			Find(&apps).Error; err != nil {
+			return err
+		}
+
+		for i := range apps {
+			purged, err := PurgeApplication(tx, &apps[i])
+			if err != nil {
+				return err
+			}
+			files = append(files, purged...)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	RemoveFiles(files)
+	return len(apps), nil
+}
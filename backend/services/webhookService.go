@@ -0,0 +1,276 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/config"
+	"github.com/JeremiasZimmerman213/internship_hub_GO/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// webhookEnvelope is the JSON body POSTed to webhook endpoints
+type webhookEnvelope struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// GenerateWebhookSecret returns a new random signing secret for an endpoint
+func GenerateWebhookSecret() string {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+	return "whsec_" + hex.EncodeToString(bytes)
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature header value for body.
+// Receivers recompute HMAC-SHA256(secret, timestamp + "." + body) and compare
+// it with v1, rejecting old timestamps to prevent replays.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// ValidateWebhookURL checks that a webhook URL is an absolute http(s) URL
+func ValidateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// EnqueueWebhookEvent queues a delivery of the event to each of the user's
+// active endpoints subscribed to eventType, as part of tx
+func EnqueueWebhookEvent(tx *gorm.DB, userID uint, eventType string, data interface{}) error {
+	var endpoints []models.WebhookEndpoint
+	if err := tx.Where("user_id = ? AND active = ?", userID, true).Find(&endpoints).Error; err != nil {
+		return err
+	}
+
+	for i := range endpoints {
+		if !endpoints[i].Subscribes(eventType) {
+			continue
+		}
+		if _, err := createWebhookDelivery(tx, &endpoints[i], eventType, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createWebhookDelivery(tx *gorm.DB, endpoint *models.WebhookEndpoint, eventType string, data interface{}) (*models.WebhookDelivery, error) {
+	eventID := make([]byte, 16)
+	rand.Read(eventID)
+
+	payload, err := json.Marshal(webhookEnvelope{
+		ID:        "evt_" + hex.EncodeToString(eventID),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := models.WebhookDelivery{
+		EndpointID:    endpoint.ID,
+		UserID:        endpoint.UserID,
+		EventType:     eventType,
+		Payload:       string(payload),
+		Status:        models.WebhookPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := tx.Create(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// SendTestWebhook sends a webhook.test event to the endpoint right away and
+// returns the logged delivery. Failed test deliveries are retried like any other.
+func SendTestWebhook(endpoint *models.WebhookEndpoint) (*models.WebhookDelivery, error) {
+	var delivery *models.WebhookDelivery
+
+	// The delivery is only committed once it has been attempted, so the
+	// worker can't pick it up and send it a second time meanwhile
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		delivery, err = createWebhookDelivery(tx, endpoint, models.WebhookTest, map[string]string{
+			"message": "This is a test event from Internship Hub.",
+		})
+		if err != nil {
+			return err
+		}
+
+		attemptWebhookDelivery(delivery, endpoint)
+		return tx.Save(delivery).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// StartWebhookWorker polls for due webhook deliveries and sends them
+func StartWebhookWorker() {
+	interval := config.GetEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for {
+				processed, err := deliverNextWebhook()
+				if err != nil {
+					log.Printf("Webhooks: failed to process deliveries: %v", err)
+				}
+				if err != nil || !processed {
+					break
+				}
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// deliverNextWebhook sends the oldest due delivery, if any. Like the email
+// outbox, the row stays locked while sending so replicas never double-send.
+func deliverNextWebhook() (bool, error) {
+	processed := false
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var delivery models.WebhookDelivery
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookPending, time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(1).
+			Find(&delivery)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		processed = true
+
+		var endpoint models.WebhookEndpoint
+		if err := tx.First(&endpoint, delivery.EndpointID).Error; err != nil || !endpoint.Active {
+			delivery.Status = models.WebhookDead
+			delivery.LastError = "endpoint was deleted or disabled"
+			return tx.Save(&delivery).Error
+		}
+
+		attemptWebhookDelivery(&delivery, &endpoint)
+		return tx.Save(&delivery).Error
+	})
+
+	return processed, err
+}
+
+// attemptWebhookDelivery POSTs the delivery once and records the outcome on
+// it, scheduling a retry with backoff on failure. The caller saves it.
+func attemptWebhookDelivery(delivery *models.WebhookDelivery, endpoint *models.WebhookEndpoint) {
+	delivery.Attempts++
+
+	code, body, err := postWebhook(endpoint, delivery)
+	delivery.ResponseCode = code
+	delivery.ResponseBody = body
+
+	if err == nil && code >= 200 && code < 300 {
+		now := time.Now()
+		delivery.Status = models.WebhookSucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+
+	if err != nil {
+		delivery.LastError = err.Error()
+	} else {
+		delivery.LastError = fmt.Sprintf("endpoint responded with status %d", code)
+	}
+
+	if delivery.Attempts >= config.GetEnvInt("WEBHOOK_MAX_ATTEMPTS", 8) {
+		delivery.Status = models.WebhookDead
+	} else {
+		delivery.Status = models.WebhookPending
+		delivery.NextAttemptAt = time.Now().Add(backoffDelay(delivery.Attempts, time.Minute, 6*time.Hour))
+	}
+}
+
+func postWebhook(endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (int, string, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "InternshipHub-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhookPayload(endpoint.Secret, time.Now().Unix(), body))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	// Only keep the start of the response for the delivery log
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return resp.StatusCode, strings.ToValidUTF8(string(responseBody), ""), nil
+}
+
+var webhookClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: blockPrivateNetworks,
+		}).DialContext,
+	},
+	// Don't follow redirects, they could point somewhere we wouldn't connect to
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// blockPrivateNetworks stops webhooks from being used to reach internal
+// services, unless WEBHOOK_ALLOW_PRIVATE_NETWORKS=true (e.g. for local scripts)
+func blockPrivateNetworks(network, address string, _ syscall.RawConn) error {
+	if config.GetEnvBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false) {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+		return fmt.Errorf("webhook address %s is not allowed", host)
+	}
+	return nil
+}